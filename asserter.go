@@ -0,0 +1,155 @@
+package testo
+
+import (
+	"maps"
+	"sync"
+	"testing"
+
+	"github.com/orsinium-labs/testo/internal/lexer"
+)
+
+// Asserter checks inputs in one test using the same default options.
+//
+// Besides the options, it accumulates state across the assertions:
+//
+//   - values captured by successful assertions, like `{"id": $id = uuid}`,
+//     which can be referenced in the following patterns as `$id`;
+//   - named patterns added with [Asserter.Define].
+//
+// Create it with [New]. It's safe for concurrent use by multiple goroutines.
+type Asserter struct {
+	t    testing.TB
+	opts []Option
+
+	mu   sync.Mutex
+	vars map[string]any
+	defs map[string]string
+}
+
+// New creates an [Asserter] for the test with the given default options.
+//
+// Options passed to the methods of the asserter are applied after the default ones.
+func New(t testing.TB, opts ...Option) *Asserter {
+	return &Asserter{
+		t:    t,
+		opts: opts,
+		vars: make(map[string]any),
+		defs: make(map[string]string),
+	}
+}
+
+// Fail the test if the given input doesn't match the expected pattern.
+//
+// See [Assert] for the supported types of input.
+func (a *Asserter) Assert(given any, expected string, opts ...Option) {
+	a.t.Helper()
+	p, captured, err := a.compile(expected, opts)
+	if err != nil {
+		a.t.Fatalf("invalid pattern: %v", err)
+	}
+	if f := p.check(given); f != nil {
+		a.t.Fatal(f.message)
+	}
+	a.remember(captured)
+}
+
+// Mark the test as failed if the given input doesn't match the expected pattern.
+//
+// Unlike [Asserter.Assert], the test continues. Returns true if the input matches.
+func (a *Asserter) Check(given any, expected string, opts ...Option) bool {
+	a.t.Helper()
+	p, captured, err := a.compile(expected, opts)
+	if err != nil {
+		a.t.Errorf("invalid pattern: %v", err)
+		return false
+	}
+	if f := p.check(given); f != nil {
+		a.t.Error(f.message)
+		return false
+	}
+	a.remember(captured)
+	return true
+}
+
+// Match the given input against the expected pattern without failing the test.
+//
+// Returns a [*SyntaxError] if the pattern is invalid, a [*MismatchError]
+// if the input doesn't match the pattern, or nil if it matches.
+func (a *Asserter) Match(given any, expected string, opts ...Option) error {
+	p, captured, err := a.compile(expected, opts)
+	if err != nil {
+		return err
+	}
+	in, err := readInput(given)
+	if err != nil {
+		return err
+	}
+	if err := p.validate(in); err != nil {
+		return err
+	}
+	a.remember(captured)
+	return nil
+}
+
+// Define a named pattern that can be referenced by name in the following patterns.
+//
+// It fails the test if the name can't be used or the pattern is invalid.
+// See [Define] for details.
+func (a *Asserter) Define(name, pattern string) {
+	a.t.Helper()
+	if !lexer.IsIdentifier(name) {
+		a.t.Fatalf("invalid name of pattern %q: must be letters and digits and not a keyword", name)
+	}
+	if _, _, err := a.compile(pattern, nil); err != nil {
+		a.t.Fatalf("invalid pattern %s: %v", name, err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.defs[name] = pattern
+}
+
+// Var returns the value captured by a successful assertion, like `$id = uuid`.
+//
+// The second returned value is false if nothing was captured with the name.
+func (a *Asserter) Var(name string) (any, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	value, found := a.vars[name]
+	return value, found
+}
+
+// compile compiles the pattern with the default options, the accumulated state, and the given options.
+//
+// The returned map is filled with the captured values when the pattern is validated.
+func (a *Asserter) compile(src string, opts []Option) (*Pattern, map[string]any, error) {
+	captured := make(map[string]any)
+	capture := func(name string, value any) {
+		captured[name] = value
+	}
+
+	a.mu.Lock()
+	all := make([]Option, 0, len(a.opts)+len(a.vars)+len(a.defs)+len(opts)+1)
+	all = append(all, a.opts...)
+	for name, value := range a.vars {
+		all = append(all, WithVar(name, value))
+	}
+	for name, pattern := range a.defs {
+		all = append(all, Define(name, pattern))
+	}
+	a.mu.Unlock()
+	all = append(all, opts...)
+	all = append(all, withCapture(capture))
+
+	p, err := Compile(src, all...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, captured, nil
+}
+
+// remember stores the values captured by a successful assertion.
+func (a *Asserter) remember(captured map[string]any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	maps.Copy(a.vars, captured)
+}
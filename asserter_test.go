@@ -0,0 +1,99 @@
+package testo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orsinium-labs/testo"
+)
+
+func TestAsserter_DefaultOptions(t *testing.T) {
+	start := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	a := testo.New(t, testo.WithVar("start", start))
+	a.Assert(`{"at": "2024-03-15T13:00:00Z"}`, `{"at": datetime(> $start)}`)
+	if a.Match(`{"at": "2024-03-15T11:00:00Z"}`, `{"at": datetime(> $start)}`) == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestAsserter_Capture(t *testing.T) {
+	a := testo.New(t)
+	a.Assert(`{"id": 13, "name": "aragorn"}`, `{"id": $id = uint, "name": string}`)
+	id, found := a.Var("id")
+	if !found || id == nil {
+		t.Fatal("expected the id to be captured")
+	}
+	a.Assert(`{"user": {"id": 13}}`, `{"user": {"id": $id}}`)
+	a.Assert(`[13]`, `[$id]`)
+	var mErr *testo.MismatchError
+	if err := a.Match(`{"user": {"id": 14}}`, `{"user": {"id": $id}}`); !errors.As(err, &mErr) {
+		t.Fatalf("expected a mismatch, got %v", err)
+	}
+}
+
+func TestAsserter_CaptureOnlyOnSuccess(t *testing.T) {
+	a := testo.New(t)
+	if a.Match(`{"id": 13, "name": 42}`, `{"id": $id = uint, "name": string}`) == nil {
+		t.Fatal("expected an error")
+	}
+	if _, found := a.Var("id"); found {
+		t.Fatal("values must not be captured from a failed assertion")
+	}
+	var sErr *testo.SyntaxError
+	if err := a.Match(`13`, `$id`); !errors.As(err, &sErr) {
+		t.Fatalf("expected an undefined variable error, got %v", err)
+	}
+}
+
+func TestAsserter_Define(t *testing.T) {
+	a := testo.New(t)
+	a.Define("user", `{"name": string, "age": uint}`)
+	a.Assert(`[{"name": "aragorn", "age": 87}]`, `[user]`)
+	err := a.Match(`{"author": {"name": "frodo", "age": -1}}`, `{"author": user}`)
+	var mErr *testo.MismatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("expected a mismatch, got %v", err)
+	}
+	m := mErr.Mismatches[0]
+	if m.Path != "/author/age" || m.Line != 1 || m.Column != 12 || m.Expected != "user" {
+		t.Fatalf("the mismatch must point to the reference: %+v", m)
+	}
+	if !strings.Contains(m.String(), "(pattern `user` at 1:12)") {
+		t.Fatalf("unexpected message: %s", m)
+	}
+
+	err = a.Match(`{}`, `{"author": usr}`)
+	if err == nil || !strings.Contains(err.Error(), `did you mean "user"?`) {
+		t.Fatalf("expected a suggestion, got %v", err)
+	}
+}
+
+func TestAsserter_DefineOption(t *testing.T) {
+	a := testo.New(t, testo.Define("point", `[int, int]`), testo.Define("line", `[point, point]`))
+	a.Assert(`[[0, 0], [3, 4]]`, `line`)
+	a.Check(`[[0, 0], [3, 4]]`, `[point, point]`)
+	err := testo.Validate(nil, `loop`, testo.Define("loop", `[loop]`))
+	if err == nil || !strings.Contains(err.Error(), "recursive reference to pattern loop") {
+		t.Fatalf("expected a recursion error, got %v", err)
+	}
+}
+
+func TestAsserter_Check(t *testing.T) {
+	ft := &fakeT{}
+	a := testo.New(ft)
+	if a.Check(`{"id": -1}`, `{"id": uint}`) {
+		t.Fatal("expected the check to fail")
+	}
+	if a.Check(`{}`, `{"id": }`) {
+		t.Fatal("expected the check to fail")
+	}
+	if ft.fatal || len(ft.errors) != 2 {
+		t.Fatalf("expected 2 non-fatal failures, got %v", ft.errors)
+	}
+	a.Define("user", `{`)
+	if !ft.fatal {
+		t.Fatal("expected a fatal failure for an invalid definition")
+	}
+}
@@ -0,0 +1,72 @@
+package testo_test
+
+import (
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+const (
+	benchPattern = `{
+		"id": uuid,
+		"name": string,
+		"age": u8,
+		"email": glob"*@example.com",
+		"created": datetime,
+		"tags": strings,
+		"address": {"city": string, "zip": intstr}
+	}`
+	benchBody = `{
+		"id": "0b6e6b2e-1c2e-4c4b-9b0a-0b6e6b2e1c2e",
+		"name": "aragorn",
+		"age": 87,
+		"email": "aragorn@example.com",
+		"created": "2024-03-15T12:00:00Z",
+		"tags": ["ranger", "king"],
+		"address": {"city": "Minas Tirith", "zip": "12345"}
+	}`
+)
+
+// BenchmarkAssert measures Assert with a literal pattern, which is compiled once and cached.
+func BenchmarkAssert(b *testing.B) {
+	for b.Loop() {
+		testo.Assert(b, benchBody, benchPattern)
+	}
+}
+
+// BenchmarkAssert_NoCache measures Assert if the pattern is compiled on every call.
+//
+// Patterns with options aren't cached, so an option forces compilation.
+func BenchmarkAssert_NoCache(b *testing.B) {
+	opt := testo.WithVar("unused", 0)
+	for b.Loop() {
+		testo.Assert(b, benchBody, benchPattern, opt)
+	}
+}
+
+// BenchmarkAssert_Parallel measures the cache contention when many tests share a pattern.
+func BenchmarkAssert_Parallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			testo.Assert(b, benchBody, benchPattern)
+		}
+	})
+}
+
+// BenchmarkPattern_Assert measures Assert on an explicitly compiled pattern.
+func BenchmarkPattern_Assert(b *testing.B) {
+	p := testo.MustCompile(benchPattern)
+	for b.Loop() {
+		p.Assert(b, benchBody)
+	}
+}
+
+// BenchmarkCompile measures how long it takes to compile the pattern.
+func BenchmarkCompile(b *testing.B) {
+	for b.Loop() {
+		_, err := testo.Compile(benchPattern)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package testo
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cacheSize is the maximum number of compiled patterns kept in the cache.
+const cacheSize = 256
+
+// patterns caches compiled patterns used by package-level functions, like [Assert].
+var patterns = newPatternCache(cacheSize)
+
+// patternCache is a concurrency-safe LRU cache of compiled patterns keyed by the pattern source.
+type patternCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	// The most recently used patterns are at the front.
+	order *list.List
+}
+
+func newPatternCache(size int) *patternCache {
+	return &patternCache{
+		size:  size,
+		items: make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// compile returns the compiled pattern, compiling it only if it's not in the cache.
+//
+// Patterns compiled with options aren't cached because options,
+// like [WithVar], are baked into the compiled pattern.
+func (c *patternCache) compile(src string, opts []Option) (*Pattern, error) {
+	if len(opts) != 0 {
+		return Compile(src, opts...)
+	}
+	c.mu.Lock()
+	if el, ok := c.items[src]; ok {
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*Pattern), nil
+	}
+	c.mu.Unlock()
+
+	// Compile outside of the lock so that slow patterns don't block other tests.
+	// Invalid patterns aren't cached: they fail the test anyway.
+	p, err := Compile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[src]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*Pattern), nil
+	}
+	c.items[src] = c.order.PushFront(p)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*Pattern).src)
+	}
+	return p, nil
+}
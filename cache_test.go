@@ -0,0 +1,69 @@
+package testo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPatternCache_Reuse(t *testing.T) {
+	c := newPatternCache(4)
+	p1, err := c.compile(`{"a": int}`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p2, _ := c.compile(`{"a": int}`, nil)
+	if p1 != p2 {
+		t.Fatal("expected the cached pattern to be reused")
+	}
+	p3, _ := c.compile(`{"a": int}`, []Option{WithVar("x", 1)})
+	if p3 == p1 {
+		t.Fatal("expected patterns with options not to be cached")
+	}
+}
+
+func TestPatternCache_Bounded(t *testing.T) {
+	c := newPatternCache(4)
+	first, _ := c.compile(`0`, nil)
+	for i := range 10 {
+		_, err := c.compile(fmt.Sprint(i+1), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(c.items) != 4 || c.order.Len() != 4 {
+		t.Fatalf("expected 4 cached patterns, got %d", len(c.items))
+	}
+	again, _ := c.compile(`0`, nil)
+	if again == first {
+		t.Fatal("expected the oldest pattern to be evicted")
+	}
+}
+
+func TestPatternCache_BadPattern(t *testing.T) {
+	c := newPatternCache(4)
+	_, err := c.compile(`{`, nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(c.items) != 0 {
+		t.Fatal("expected invalid patterns not to be cached")
+	}
+}
+
+func TestPatternCache_Concurrent(t *testing.T) {
+	c := newPatternCache(8)
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			_, err := c.compile(fmt.Sprintf(`[%d, string]`, i%16), nil)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if c.order.Len() > 8 {
+		t.Fatalf("cache exceeded its size: %d", c.order.Len())
+	}
+}
@@ -0,0 +1,117 @@
+package testo_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errors   []string
+	fatal    bool
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+// finish runs the cleanup functions, like the test runner does when the test finishes.
+func (t *fakeT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func (t *fakeT) Error(args ...any) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatal(args ...any) {
+	t.Error(args...)
+	t.fatal = true
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	t.fatal = true
+}
+
+func TestCheck(t *testing.T) {
+	ft := &fakeT{}
+	if !testo.Check(ft, `{"id": 1}`, `{"id": uint}`) {
+		t.Fatal("expected the check to pass")
+	}
+	if testo.Check(ft, `{"id": -1}`, `{"id": uint}`) {
+		t.Fatal("expected the check to fail")
+	}
+	if testo.Check(ft, `{"id": "x"}`, `{"id": uint}`) {
+		t.Fatal("expected the check to fail")
+	}
+	if ft.fatal {
+		t.Fatal("Check must not stop the test")
+	}
+	if len(ft.errors) != 2 {
+		t.Fatalf("expected 2 reported failures, got %d: %v", len(ft.errors), ft.errors)
+	}
+	if !strings.Contains(ft.errors[0], "validation error:") {
+		t.Fatalf("unexpected message: %s", ft.errors[0])
+	}
+}
+
+func TestCheck_BadPattern(t *testing.T) {
+	ft := &fakeT{}
+	if testo.Check(ft, `{}`, `{"id": }`) {
+		t.Fatal("expected the check to fail")
+	}
+	if ft.fatal || len(ft.errors) != 1 || !strings.HasPrefix(ft.errors[0], "invalid pattern:") {
+		t.Fatalf("unexpected failures: %v", ft.errors)
+	}
+}
+
+func TestAssert_TB(t *testing.T) {
+	ft := &fakeT{}
+	testo.Assert(ft, `[1, 2]`, `[int, string]`)
+	if !ft.fatal || len(ft.errors) != 1 {
+		t.Fatalf("expected a fatal failure, got %v", ft.errors)
+	}
+}
+
+func TestAssertAt(t *testing.T) {
+	body := `{"data": {"items": [{"id": 1, "price": 5}, {"id": 3, "price": -1}]}}`
+	testo.AssertAt(t, body, "/data/items/0", `{"id": 1, "price": uint}`)
+	testo.AssertAt(t, body, "$.data.items[*].id", `uint`)
+
+	ft := &fakeT{}
+	testo.AssertAt(ft, body, "$.data.items[?(@.id==3)]", `{"id": 3, "price": uint}`)
+	if !ft.fatal || len(ft.errors) != 1 {
+		t.Fatalf("expected a fatal failure, got %v", ft.errors)
+	}
+	msg := ft.errors[0]
+	for _, want := range []string{
+		"validation error at `$.data.items[?(@.id==3)]`:",
+		"input:1:63: data.items[1].price: must be greater than or equal to 0",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("the message doesn't contain %q:\n%s", want, msg)
+		}
+	}
+
+	for _, selector := range []string{"$.data.users", "data"} {
+		ft = &fakeT{}
+		testo.AssertAt(ft, body, selector, `any`)
+		if !ft.fatal {
+			t.Fatalf("expected a failure for %q", selector)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Command testo is a helper for writing testo patterns.
+//
+// Usage:
+//
+//	testo infer [FILE...]
+//
+// The infer subcommand prints a pattern matching all the given JSON documents.
+// If no files are given, the documents are read from stdin, one after another.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/orsinium-labs/testo"
+)
+
+const usage = `usage: testo infer [FILE...]
+
+Print a pattern matching all the given JSON documents.
+If no files are given, the documents are read from stdin.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "infer":
+		err := infer(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "testo: %v\n", err)
+			os.Exit(1)
+		}
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "testo: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// infer prints the pattern inferred from the JSON files or, if there are none, from stdin.
+func infer(paths []string) error {
+	samples := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !json.Valid(raw) {
+			return fmt.Errorf("%s: invalid JSON", path)
+		}
+		samples = append(samples, raw)
+	}
+	if len(paths) == 0 {
+		decoder := json.NewDecoder(os.Stdin)
+		for {
+			var raw json.RawMessage
+			err := decoder.Decode(&raw)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("read stdin: %v", err)
+			}
+			samples = append(samples, raw)
+		}
+	}
+	if len(samples) == 0 {
+		return errors.New("no JSON documents given")
+	}
+	fmt.Println(testo.Infer(samples...))
+	return nil
+}
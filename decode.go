@@ -0,0 +1,69 @@
+package testo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Validate the given input with the expected pattern and decode it into a value of type T.
+//
+// The input is read only once, so it can be an [io.Reader] like the body of [net/http.Response].
+// See [Assert] for the supported types of input. If the input doesn't match
+// the pattern, the returned error is a [*MismatchError] and nothing is decoded.
+func Decode[T any](given any, expected string, opts ...Option) (T, error) {
+	var res T
+	p, err := patterns.compile(expected, opts)
+	if err != nil {
+		return res, err
+	}
+	in, err := readInput(given)
+	if err != nil {
+		return res, err
+	}
+	if err := p.validate(in); err != nil {
+		return res, err
+	}
+	err = in.decode(&res)
+	return res, err
+}
+
+// Fail tests if the given input doesn't match the expected pattern, otherwise decode it into a value of type T.
+//
+// Like [Decode], the input is read only once. See [Assert] for the supported types of input.
+func AssertDecode[T any](t testing.TB, given any, expected string, opts ...Option) T {
+	t.Helper()
+	var res T
+	p, err := patterns.compile(expected, opts)
+	if err != nil {
+		t.Fatalf("invalid pattern: %v", err)
+		return res
+	}
+	in, err := readInput(given)
+	if err != nil {
+		t.Fatalf("failed to read input: %v", err)
+		return res
+	}
+	if f := p.explain(in, p.mismatches(in)); f != nil {
+		t.Fatal(f.message)
+		return res
+	}
+	if err := in.decode(&res); err != nil {
+		t.Fatalf("failed to decode input into %T: %v", res, err)
+	}
+	return res
+}
+
+// decode unmarshals the input into the target.
+//
+// The JSON input is decoded from the original text. Other values are converted into JSON first.
+func (in input) decode(target any) error {
+	raw := in.raw
+	if raw == nil {
+		var err error
+		raw, err = json.Marshal(in.value)
+		if err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(raw, target)
+}
@@ -0,0 +1,55 @@
+package testo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+type user struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestDecode(t *testing.T) {
+	body := strings.NewReader(`{"name": "aragorn", "age": 87}`)
+	u, err := testo.Decode[user](body, `{"name": string, "age": uint}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u != (user{Name: "aragorn", Age: 87}) {
+		t.Fatalf("unexpected value: %+v", u)
+	}
+
+	_, err = testo.Decode[user](`{"name": "aragorn", "age": -1}`, `{"name": string, "age": uint}`)
+	var mErr *testo.MismatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("expected a mismatch, got %v", err)
+	}
+
+	_, err = testo.Decode[user](`{"name": 1}`, `{"name": int}`)
+	if err == nil {
+		t.Fatal("expected a decoding error")
+	}
+
+	m, err := testo.Decode[map[string]int](map[string]any{"a": 1}, `{"a": int}`)
+	if err != nil || m["a"] != 1 {
+		t.Fatalf("unexpected result: %v, %v", m, err)
+	}
+}
+
+func TestAssertDecode(t *testing.T) {
+	body := strings.NewReader(`[{"name": "aragorn", "age": 87}, {"name": "legolas", "age": 2931}]`)
+	users := testo.AssertDecode[[]user](t, body, `[{"name": "aragorn", "age": uint}, {"name": string, "age": uint}]`)
+	if len(users) != 2 || users[1].Name != "legolas" {
+		t.Fatalf("unexpected value: %+v", users)
+	}
+
+	ft := &fakeT{}
+	testo.AssertDecode[user](ft, `{"name": "aragorn"}`, `{"name": string, "age": uint}`)
+	if !ft.fatal || !strings.HasPrefix(ft.errors[0], "validation error:") {
+		t.Fatalf("expected a validation failure, got %v", ft.errors)
+	}
+}
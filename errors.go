@@ -0,0 +1,310 @@
+package testo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/orsinium-labs/testo/internal/parser"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// maxFragment is the maximum length of the pattern fragment shown in error messages.
+const maxFragment = 40
+
+// MismatchError is the error returned when the input doesn't match the pattern.
+//
+// Use [errors.As] to access the list of mismatches:
+//
+//	var mErr *testo.MismatchError
+//	if errors.As(err, &mErr) {
+//		for _, m := range mErr.Mismatches {
+//			fmt.Println(m.Path, m.Actual)
+//		}
+//	}
+//
+// The error unwraps into the underlying [valdo.Error].
+type MismatchError struct {
+	// Mismatches in the order they were found in the input.
+	//
+	// See [MaxErrors] to limit the number of reported mismatches.
+	Mismatches []Mismatch
+	// The number of mismatches not included in the list because of the limit.
+	Omitted int
+	// The error returned by the validator.
+	Err valdo.Error
+
+	// The name of the input shown with positions of mismatches.
+	input string
+	// The selector of the validated values, if the pattern is applied only to a part of the input.
+	selector string
+}
+
+// newMismatchError converts a validation error into a [MismatchError].
+//
+// Mismatches at the ignored paths are dropped. If nothing is left, nil is returned.
+// If maxErrors is not 0, at most that many mismatches are included.
+func newMismatchError(err valdo.Error, maxErrors int, ignore []string) *MismatchError {
+	vErr := parser.ValidationError{Err: err}
+	ms := vErr.Mismatches()
+	if len(ignore) > 0 {
+		ms = slices.DeleteFunc(ms, func(m parser.Mismatch) bool {
+			return slices.ContainsFunc(ignore, func(path string) bool {
+				return isUnder(m.Path, path)
+			})
+		})
+		if len(ms) == 0 {
+			return nil
+		}
+	}
+	res := &MismatchError{Err: err}
+	if maxErrors > 0 && len(ms) > maxErrors {
+		res.Omitted = len(ms) - maxErrors
+		ms = ms[:maxErrors]
+	}
+	res.Mismatches = make([]Mismatch, 0, len(ms))
+	for _, m := range ms {
+		res.Mismatches = append(res.Mismatches, Mismatch{
+			Path:     m.Path,
+			Segments: m.Segments,
+			Expected: m.Expected,
+			Actual:   m.Actual,
+			Line:     m.Line,
+			Column:   m.Column,
+			Err:      m.Err,
+		})
+	}
+	return res
+}
+
+// isUnder checks if the JSON Pointer is the given one or points inside of it.
+//
+// The "*" reference token in the parent matches any property or index,
+// like in "/items/*/id". The paths continue into the values decoded from strings,
+// so "/payload" also covers the mismatches inside of `json(...)` at "/payload".
+func isUnder(path, parent string) bool {
+	if parent == "" {
+		return true
+	}
+	tokens := strings.Split(path, "/")
+	parents := strings.Split(parent, "/")
+	if len(parents) > len(tokens) {
+		return false
+	}
+	for i, want := range parents {
+		got := tokens[i]
+		if want != "*" && want != got {
+			return false
+		}
+	}
+	return true
+}
+
+// locate finds positions of the mismatched values in the input.
+//
+// The positions are known only if the input is JSON text.
+func (e *MismatchError) locate(in input) {
+	e.input = in.name
+	if in.raw == nil {
+		return
+	}
+	positions := parser.ScanPositions(in.raw)
+	for i, m := range e.Mismatches {
+		// A value decoded from a string is reported at the position of the string.
+		line, column, ok := positions.Find(parser.Pointer(parser.Undecoded(m.Segments)))
+		if ok {
+			e.Mismatches[i].InputLine = line
+			e.Mismatches[i].InputColumn = column
+		}
+	}
+}
+
+// Error implements [error] interface.
+func (e *MismatchError) Error() string {
+	lines := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		location := m.location()
+		if location == "" {
+			lines = append(lines, m.Err.Error())
+		} else {
+			lines = append(lines, location+": "+m.Err.Error())
+		}
+	}
+	if e.Omitted > 0 {
+		lines = append(lines, fmt.Sprintf("and %d more", e.Omitted))
+	}
+	return strings.Join(lines, "; ")
+}
+
+// Unwrap makes errors.Is and errors.As work for the underlying valdo error.
+func (e *MismatchError) Unwrap() error {
+	return e.Err
+}
+
+// Mismatch is a single value in the input that doesn't match the pattern.
+type Mismatch struct {
+	// The JSON Pointer (RFC 6901) to the value, like "/users/0/name".
+	//
+	// Values decoded from strings, like in `json(...)`, don't add reference tokens,
+	// so the pointer continues into the decoded value as if it replaced the string,
+	// like "/payload/a". See Segments to find where the values are decoded.
+	Path string
+	// The steps from the root of the input to the value, the same as in Path
+	// but with values decoded from strings and errors in object keys marked.
+	Segments []Segment
+	// The part of the pattern the value was matched against, like "int(1..10)".
+	//
+	// It's empty for unexpected properties.
+	Expected string
+	// The mismatched value. It's nil if the value is missing.
+	Actual any
+	// The position of the expected value in the pattern.
+	Line   int
+	Column int
+	// The position of the value in the input, or zeros if the input isn't JSON text.
+	//
+	// For a missing value, it's the position of the object that should contain it.
+	InputLine   int
+	InputColumn int
+	// The error reported for the value.
+	Err valdo.Error
+}
+
+// String describes the mismatch in a single line.
+//
+// The path is shown with values decoded from strings marked by the decoder name
+// in parentheses, like "/payload(json)/a" or "/(json)/a" at the root,
+// and errors in object keys marked with "(key)", like "/users/bob(key)".
+func (m Mismatch) String() string {
+	location := m.location()
+	if location == "" {
+		return m.message()
+	}
+	return location + ": " + m.message()
+}
+
+// location formats the path for error messages, see [Mismatch.String].
+func (m Mismatch) location() string {
+	return parser.FormatPath(m.Segments)
+}
+
+// Segment is a step on the path from the root of the input to a mismatched value.
+//
+// Its Kind is one of [SegmentProperty], [SegmentIndex], [SegmentKey], or [SegmentDecoded].
+// Name is the name of the property or the key, or the name of the decoder, like "json".
+// Index is the index of the array item.
+type Segment = parser.Segment
+
+// SegmentKind is the kind of a [Segment].
+type SegmentKind = parser.SegmentKind
+
+// Kinds of [Segment].
+const (
+	// A property of an object.
+	SegmentProperty = parser.SegmentProperty
+	// An item of an array.
+	SegmentIndex = parser.SegmentIndex
+	// A key of an object that doesn't match the pattern, like in `map[uuid]int`.
+	SegmentKey = parser.SegmentKey
+	// A value decoded from a string, like in `json(...)`.
+	SegmentDecoded = parser.SegmentDecoded
+)
+
+// message describes the mismatch without the path.
+func (m Mismatch) message() string {
+	if m.Expected == "" {
+		return m.Err.Error()
+	}
+	return fmt.Sprintf("%s (pattern `%s` at %d:%d)", m.Err.Error(), shortFragment(m.Expected), m.Line, m.Column)
+}
+
+// shortFragment collapses whitespace in the pattern fragment and truncates it.
+func shortFragment(fragment string) string {
+	fragment = strings.Join(strings.Fields(fragment), " ")
+	runes := []rune(fragment)
+	if len(runes) > maxFragment {
+		return string(runes[:maxFragment-1]) + "…"
+	}
+	return fragment
+}
+
+// SyntaxError is the error returned when the pattern cannot be parsed.
+//
+// The error message includes the offending line of the pattern
+// with a caret pointing to the error position.
+type SyntaxError struct {
+	// The position of the error in the pattern.
+	Line   int
+	Column int
+	// The byte offset of the error in the pattern.
+	Offset int
+	// The description of the error, like "expected ':' after key \"name\", got keyword int".
+	Msg string
+	// The source text of the pattern.
+	Source string
+}
+
+// newSyntaxError converts a parsing error into a [SyntaxError] or [SyntaxErrors].
+func newSyntaxError(err error, src string) error {
+	var pErrs parser.SyntaxErrors
+	if errors.As(err, &pErrs) {
+		res := make(SyntaxErrors, len(pErrs))
+		for i, pErr := range pErrs {
+			res[i] = convertSyntaxError(pErr, src)
+		}
+		return res
+	}
+	var pErr *parser.SyntaxError
+	if errors.As(err, &pErr) {
+		return convertSyntaxError(pErr, src)
+	}
+	return err
+}
+
+// convertSyntaxError converts a syntax error from the parser into a [SyntaxError].
+func convertSyntaxError(err *parser.SyntaxError, src string) *SyntaxError {
+	return &SyntaxError{
+		Line:   err.Line,
+		Column: err.Column,
+		Offset: err.Offset,
+		Msg:    err.Msg,
+		Source: src,
+	}
+}
+
+// Error implements [error] interface.
+func (e *SyntaxError) Error() string {
+	header := fmt.Sprintf("syntax error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	srcLines := strings.Split(e.Source, "\n")
+	snippet := renderSnippet(srcLines, e.Line, e.Column, len(strconv.Itoa(e.Line)), false)
+	if snippet == "" {
+		return header
+	}
+	return header + "\n" + strings.TrimRight(snippet, "\n")
+}
+
+// SyntaxErrors is the error returned when the pattern has more than one syntax error.
+//
+// Use [errors.As] with [*SyntaxError] to get the first error
+// or with [SyntaxErrors] to get all of them.
+type SyntaxErrors []*SyntaxError
+
+// Error implements [error] interface.
+func (es SyntaxErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap makes errors.Is and errors.As work for every error in the list.
+func (es SyntaxErrors) Unwrap() []error {
+	res := make([]error, len(es))
+	for i, e := range es {
+		res[i] = e
+	}
+	return res
+}
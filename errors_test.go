@@ -0,0 +1,162 @@
+package testo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+func TestMismatchError(t *testing.T) {
+	pattern := "{\n  \"name\": string,\n  \"tags\": [string, int(1..10)],\n  \"age\": uint\n}"
+	err := testo.ValidateJSON(`{"tags": ["a", 11], "age": -3, "extra": 1}`, pattern)
+	var mErr *testo.MismatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("expected MismatchError, got %T", err)
+	}
+	expected := []struct {
+		path     string
+		fragment string
+		actual   any
+		line     int
+		column   int
+	}{
+		{"/name", "string", nil, 2, 11},
+		{"/tags/1", "int(1..10)", json.Number("11"), 3, 20},
+		{"/age", "uint", json.Number("-3"), 4, 10},
+		{"/extra", "", json.Number("1"), 1, 1},
+	}
+	if len(mErr.Mismatches) != len(expected) {
+		t.Fatalf("expected %d mismatches, got %d: %v", len(expected), len(mErr.Mismatches), err)
+	}
+	for i, exp := range expected {
+		m := mErr.Mismatches[i]
+		if m.Path != exp.path || m.Expected != exp.fragment || m.Actual != exp.actual {
+			t.Errorf("mismatch %d: unexpected %q %q %v", i, m.Path, m.Expected, m.Actual)
+		}
+		if m.Line != exp.line || m.Column != exp.column {
+			t.Errorf("mismatch %d: unexpected position %d:%d", i, m.Line, m.Column)
+		}
+	}
+}
+
+func TestMismatchError_Unwrap(t *testing.T) {
+	err := testo.Validate(map[string]any{"age": "old"}, `{"age": int}`)
+	var typeErr valdo.ErrType
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected valdo.ErrType, got %v", err)
+	}
+	if typeErr.Got != "string" {
+		t.Fatalf("unexpected type: %s", typeErr.Got)
+	}
+}
+
+func TestMismatchError_Decoded(t *testing.T) {
+	err := testo.ValidateJSON(`{"payload": "{\"a\": 2}"}`, `{"payload": json({"a": 1})}`)
+	var mErr *testo.MismatchError
+	if !errors.As(err, &mErr) || len(mErr.Mismatches) != 1 {
+		t.Fatalf("expected a single mismatch, got %v", err)
+	}
+	m := mErr.Mismatches[0]
+	if m.Path != "/payload/a" || m.Expected != "1" || m.Actual != json.Number("2") {
+		t.Fatalf("unexpected mismatch: %+v", m)
+	}
+	segments := []testo.Segment{
+		{Kind: testo.SegmentProperty, Name: "payload"},
+		{Kind: testo.SegmentDecoded, Name: "json"},
+		{Kind: testo.SegmentProperty, Name: "a"},
+	}
+	if !slices.Equal(m.Segments, segments) {
+		t.Fatalf("unexpected segments: %+v", m.Segments)
+	}
+	if !strings.HasPrefix(m.String(), "/payload(json)/a: ") {
+		t.Fatalf("unexpected message: %s", m)
+	}
+
+	// A decoded value at the root and a key with a parenthesis.
+	err = testo.ValidateJSON(`"{\"f(x)\": \"a\"}"`, `json({"f(x)": int})`)
+	if !errors.As(err, &mErr) || len(mErr.Mismatches) != 1 {
+		t.Fatalf("expected a single mismatch, got %v", err)
+	}
+	m = mErr.Mismatches[0]
+	if m.Path != "/f(x)" || !strings.HasPrefix(m.String(), "/(json)/f(x): ") {
+		t.Fatalf("unexpected mismatch: %s", m)
+	}
+}
+
+func TestMismatchError_MisspelledKey(t *testing.T) {
+	err := testo.ValidateJSON(`{"userName": "aragorn", "age": 82}`, `{"username": string, "age": int}`)
+	var mErr *testo.MismatchError
+	if !errors.As(err, &mErr) || len(mErr.Mismatches) != 1 {
+		t.Fatalf("expected a single mismatch, got %v", err)
+	}
+	m := mErr.Mismatches[0]
+	if m.Path != "/username" || !strings.Contains(m.String(), "did you mean `userName`?") {
+		t.Fatalf("unexpected mismatch: %s", m)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	pattern := "{\n  \"name\" string\n}"
+	_, err := testo.Compile(pattern)
+	var sErr *testo.SyntaxError
+	if !errors.As(err, &sErr) {
+		t.Fatalf("expected SyntaxError, got %T", err)
+	}
+	if sErr.Line != 2 || sErr.Column != 10 || sErr.Offset != 11 {
+		t.Fatalf("unexpected position: %d:%d (offset %d)", sErr.Line, sErr.Column, sErr.Offset)
+	}
+	expected := strings.Join([]string{
+		`syntax error at line 2, column 10: expected ':' after key "name", got keyword string`,
+		`2 |   "name" string`,
+		`  |          ^`,
+	}, "\n")
+	if err.Error() != expected {
+		t.Fatalf("unexpected message:\n%s", err)
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	_, err := testo.Compile(`{"a": strng, "b": [1 2]}`)
+	var sErrs testo.SyntaxErrors
+	if !errors.As(err, &sErrs) {
+		t.Fatalf("expected SyntaxErrors, got %T", err)
+	}
+	if len(sErrs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(sErrs))
+	}
+	var sErr *testo.SyntaxError
+	if !errors.As(err, &sErr) || sErr.Column != 7 {
+		t.Fatalf("expected the first error to be at column 7, got %v", sErr)
+	}
+	if sErrs[1].Column != 22 {
+		t.Fatalf("expected the second error to be at column 22, got %d", sErrs[1].Column)
+	}
+}
+
+func TestMismatchError_InputPosition(t *testing.T) {
+	given := "{\n  \"name\": \"aragorn\",\n  \"age\": -3\n}"
+	err := testo.ValidateJSON(given, `{"name": string, "age": uint, "email": string}`)
+	var mErr *testo.MismatchError
+	if !errors.As(err, &mErr) || len(mErr.Mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %v", err)
+	}
+	age := mErr.Mismatches[0]
+	if age.Path != "/age" || age.InputLine != 3 || age.InputColumn != 10 {
+		t.Fatalf("unexpected position of %s: %d:%d", age.Path, age.InputLine, age.InputColumn)
+	}
+	// The position of a missing property is the position of the object.
+	email := mErr.Mismatches[1]
+	if email.Path != "/email" || email.InputLine != 1 || email.InputColumn != 1 {
+		t.Fatalf("unexpected position of %s: %d:%d", email.Path, email.InputLine, email.InputColumn)
+	}
+
+	err = testo.Validate(map[string]any{"age": -3}, `{"age": uint}`)
+	if !errors.As(err, &mErr) || mErr.Mismatches[0].InputLine != 0 {
+		t.Fatalf("expected no position for Go values, got %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package testo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/orsinium-labs/testo/internal/parser"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+const (
+	// maxValue is the maximum length of a value shown in the input excerpt.
+	maxValue = 60
+	// maxSnippets is the maximum number of pattern lines shown in the failure message.
+	maxSnippets = 5
+)
+
+// ANSI escape sequences used to highlight the failure message.
+const (
+	colorRed   = "\x1b[31m"
+	colorCyan  = "\x1b[36m"
+	colorReset = "\x1b[0m"
+)
+
+// useColor reports whether the failure message should be colored.
+var useColor = sync.OnceValue(colorEnabled)
+
+// colorEnabled reports whether the output supports ANSI colors.
+//
+// The NO_COLOR and FORCE_COLOR environment variables take precedence
+// over the detection of a terminal.
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("FORCE_COLOR") != "" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// paint wraps the text into the color escape sequences if colors are enabled.
+func paint(text, color string, enabled bool) string {
+	if !enabled {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// excerptLine is a line of the input excerpt.
+type excerptLine struct {
+	text string
+	// The description of the mismatch at this line, if any.
+	note string
+}
+
+// excerptNode is a value in the input that contains mismatches.
+type excerptNode struct {
+	// Values inside of the node that contain mismatches, by the object key or array index.
+	children map[string]*excerptNode
+	notes    []string
+	// If true, the value isn't present in the input.
+	missing bool
+}
+
+// add registers the mismatch in the tree of nodes.
+//
+// Mismatches inside of decoded values, like `json(...)`, are attached to the string
+// containing them, and mismatches in object keys are attached to the property.
+func (n *excerptNode) add(m Mismatch) {
+	node := n
+	for _, s := range m.Segments {
+		if s.Kind == SegmentDecoded {
+			node.notes = append(node.notes, "("+s.Name+") "+m.message())
+			return
+		}
+		key := s.Name
+		if s.Kind == SegmentIndex {
+			key = strconv.Itoa(s.Index)
+		}
+		if node.children == nil {
+			node.children = make(map[string]*excerptNode)
+		}
+		child, found := node.children[key]
+		if !found {
+			child = &excerptNode{}
+			node.children[key] = child
+		}
+		node = child
+		if s.Kind == SegmentKey {
+			node.notes = append(node.notes, "(key) "+m.message())
+			return
+		}
+	}
+	switch e := m.Err.(type) {
+	case valdo.ErrRequired:
+		node.missing = true
+		if e.Format != "" {
+			// The message includes a suggestion of a similar key.
+			node.notes = append(node.notes, e.Error())
+		} else {
+			node.notes = append(node.notes, "missing, expected "+shortFragment(m.Expected))
+		}
+	case valdo.ErrUnexpected:
+		if e.Format != "" {
+			node.notes = append(node.notes, e.Error())
+		} else {
+			node.notes = append(node.notes, "unexpected property")
+		}
+	default:
+		switch m.Actual.(type) {
+		case map[string]any, []any:
+			node.notes = append(node.notes, m.Err.Error())
+		default:
+			if m.Expected == "" {
+				node.notes = append(node.notes, m.Err.Error())
+			} else {
+				note := fmt.Sprintf("expected %s, got %s", shortFragment(m.Expected), compactValue(m.Actual))
+				node.notes = append(node.notes, note)
+			}
+		}
+	}
+}
+
+// renderExcerpt renders the parts of the input that contain the mismatches.
+//
+// Values without mismatches are elided as "...".
+func renderExcerpt(data any, ms []Mismatch, color bool) string {
+	root := &excerptNode{}
+	for _, m := range ms {
+		root.add(m)
+	}
+	var b strings.Builder
+	for i, line := range root.render(data, "", "") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line.text)
+		if line.note != "" {
+			b.WriteString("  ")
+			b.WriteString(paint("<-- "+line.note, colorRed, color))
+		}
+	}
+	return b.String()
+}
+
+// render renders the value of the node, prefixed by the given text, like the object key.
+func (n *excerptNode) render(value any, indent, prefix string) []excerptLine {
+	note := strings.Join(n.notes, "; ")
+	if n.missing {
+		return []excerptLine{{text: indent + prefix + "<missing>", note: note}}
+	}
+	if len(n.children) == 0 {
+		return []excerptLine{{text: indent + prefix + compactValue(value), note: note}}
+	}
+
+	var open, close string
+	var entries [][]excerptLine
+	inner := indent + "  "
+	switch v := value.(type) {
+	case map[string]any:
+		open, close = "{", "}"
+		names := parser.SortedKeys(v)
+		for name := range n.children {
+			if _, found := v[name]; !found {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		entries = n.renderEntries(names, inner, func(name string) (any, string) {
+			return v[name], strconv.Quote(name) + ": "
+		})
+	case []any:
+		open, close = "[", "]"
+		indices := make([]string, len(v))
+		for i := range v {
+			indices[i] = strconv.Itoa(i)
+		}
+		entries = n.renderEntries(indices, inner, func(index string) (any, string) {
+			i, _ := strconv.Atoi(index)
+			return v[i], ""
+		})
+	default:
+		return []excerptLine{{text: indent + prefix + compactValue(value), note: note}}
+	}
+
+	lines := []excerptLine{{text: indent + prefix + open, note: note}}
+	for i, entry := range entries {
+		isEllipsis := len(entry) == 1 && entry[0].text == inner+"..."
+		if i < len(entries)-1 && !isEllipsis {
+			entry[len(entry)-1].text += ","
+		}
+		lines = append(lines, entry...)
+	}
+	return append(lines, excerptLine{text: indent + close})
+}
+
+// renderEntries renders items of an object or array with the given keys.
+//
+// Consecutive items without mismatches are collapsed into a single "..." line.
+func (n *excerptNode) renderEntries(keys []string, indent string, get func(string) (any, string)) [][]excerptLine {
+	entries := make([][]excerptLine, 0)
+	skipped := false
+	for _, key := range keys {
+		child, found := n.children[key]
+		if !found {
+			if !skipped {
+				entries = append(entries, []excerptLine{{text: indent + "..."}})
+			}
+			skipped = true
+			continue
+		}
+		skipped = false
+		value, prefix := get(key)
+		entries = append(entries, child.render(value, indent, prefix))
+	}
+	return entries
+}
+
+// compactValue serializes the value into a single line, truncating it if it's too long.
+func compactValue(value any) string {
+	raw, err := json.Marshal(value)
+	text := string(raw)
+	if err != nil {
+		text = fmt.Sprintf("%v", value)
+	}
+	runes := []rune(text)
+	if len(runes) > maxValue {
+		return string(runes[:maxValue-1]) + "…"
+	}
+	return text
+}
+
+// renderSnippets renders the pattern lines the mismatches point to, with a caret under the position.
+func renderSnippets(src string, ms []Mismatch, color bool) string {
+	type position struct{ line, column int }
+	seen := make(map[position]bool)
+	positions := make([]position, 0)
+	for _, m := range ms {
+		pos := position{m.Line, m.Column}
+		if m.Expected == "" || seen[pos] {
+			continue
+		}
+		seen[pos] = true
+		positions = append(positions, pos)
+	}
+	srcLines := strings.Split(src, "\n")
+	width := len(strconv.Itoa(len(srcLines)))
+	var b strings.Builder
+	for i, pos := range positions {
+		if i == maxSnippets {
+			fmt.Fprintf(&b, "... and %d more\n", len(positions)-maxSnippets)
+			break
+		}
+		b.WriteString(renderSnippet(srcLines, pos.line, pos.column, width, color))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderSnippet renders the source line with a caret under the given column.
+//
+// The width is the number of digits reserved for line numbers.
+func renderSnippet(srcLines []string, line, column, width int, color bool) string {
+	if line < 1 || line > len(srcLines) {
+		return ""
+	}
+	text := strings.TrimRight(srcLines[line-1], "\r")
+	// Keep tabs so that the caret is aligned with the line above.
+	var pad strings.Builder
+	for _, ch := range text[:max(min(column-1, len(text)), 0)] {
+		if ch == '\t' {
+			pad.WriteByte('\t')
+		} else {
+			pad.WriteByte(' ')
+		}
+	}
+	return fmt.Sprintf(
+		"%*d | %s\n%*s | %s%s\n",
+		width, line, text,
+		width, "", pad.String(), paint("^", colorCyan, color),
+	)
+}
@@ -2,6 +2,8 @@ module github.com/orsinium-labs/testo
 
 go 1.25.4
 
-require github.com/orsinium-labs/valdo v1.3.0
-
-require github.com/orsinium-labs/jsony v1.2.0 // indirect
+require (
+	github.com/orsinium-labs/jsony v1.2.0
+	github.com/orsinium-labs/valdo v1.3.0
+	golang.org/x/text v0.31.0
+)
@@ -0,0 +1,202 @@
+package testo
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/orsinium-labs/testo/internal/parser"
+)
+
+// formats are the keywords for strings in a known format, detected by [Infer].
+//
+// If a string matches several formats, the first one is used.
+var formats = []struct {
+	keyword string
+	pattern *Pattern
+}{
+	{"uuid", MustCompile(`uuid`)},
+	{"datetime", MustCompile(`datetime`)},
+	{"date", MustCompile(`date`)},
+}
+
+// inferIndent is the indentation of nested values in the inferred pattern.
+const inferIndent = "    "
+
+// Infer generalizes the sample JSON documents into a pattern that matches all of them.
+//
+// Literal values become keywords, like `int` or `string`, and strings in a known format
+// become `uuid`, `datetime`, or `date`. Arrays become homogeneous, like `[int...]`,
+// with items of all arrays in all samples merged together. Properties missing
+// in some of the objects become optional, like `"nickname"?: string`.
+// If the values at the same place have different types, they become `any`.
+//
+// Samples that aren't valid JSON are ignored. The pattern is formatted
+// with every property of an object on a separate line.
+func Infer(samples ...[]byte) string {
+	root := &shape{}
+	for _, sample := range samples {
+		value, err := parser.DecodeJSON(sample)
+		if err != nil {
+			continue
+		}
+		root.add(value)
+	}
+	var b strings.Builder
+	root.render(&b, "")
+	return b.String()
+}
+
+// shape accumulates what is known about the values at the same place in the samples.
+type shape struct {
+	// The number of values of each type.
+	nulls   int
+	bools   int
+	ints    int
+	floats  int
+	strings int
+	objects int
+	arrays  int
+	// The number of strings matching each of the known formats.
+	formats []int
+
+	// Properties of all objects in the order they were first seen.
+	props map[string]*shape
+	keys  []string
+
+	// Items of all arrays.
+	items *shape
+}
+
+// add merges the value decoded from JSON into the shape.
+func (s *shape) add(value any) {
+	switch val := value.(type) {
+	case nil:
+		s.nulls++
+	case bool:
+		s.bools++
+	case json.Number:
+		if strings.ContainsAny(string(val), ".eE") {
+			s.floats++
+		} else {
+			s.ints++
+		}
+	case string:
+		s.strings++
+		if s.formats == nil {
+			s.formats = make([]int, len(formats))
+		}
+		for i, f := range formats {
+			if f.pattern.Validate(val) == nil {
+				s.formats[i]++
+			}
+		}
+	case map[string]any:
+		s.objects++
+		if s.props == nil {
+			s.props = make(map[string]*shape)
+		}
+		for _, key := range parser.SortedKeys(val) {
+			prop, found := s.props[key]
+			if !found {
+				prop = &shape{}
+				s.props[key] = prop
+				s.keys = append(s.keys, key)
+			}
+			prop.add(val[key])
+		}
+	case []any:
+		s.arrays++
+		if s.items == nil {
+			s.items = &shape{}
+		}
+		for _, item := range val {
+			s.items.add(item)
+		}
+	}
+}
+
+// count returns the number of values merged into the shape.
+func (s *shape) count() int {
+	return s.nulls + s.bools + s.ints + s.floats + s.strings + s.objects + s.arrays
+}
+
+// render writes the pattern matching all values merged into the shape.
+//
+// The indent is the indentation of the line where the value starts.
+func (s *shape) render(b *strings.Builder, indent string) {
+	kinds := 0
+	for _, n := range []int{s.nulls, s.bools, s.ints + s.floats, s.strings, s.objects, s.arrays} {
+		if n > 0 {
+			kinds++
+		}
+	}
+	switch {
+	case kinds != 1:
+		b.WriteString("any")
+	case s.nulls > 0:
+		b.WriteString("null")
+	case s.bools > 0:
+		b.WriteString("bool")
+	case s.floats > 0:
+		b.WriteString("float")
+	case s.ints > 0:
+		b.WriteString("int")
+	case s.strings > 0:
+		b.WriteString(s.stringKeyword())
+	case s.objects > 0:
+		s.renderObject(b, indent)
+	case s.items.count() == 0:
+		// All arrays are empty, so nothing is known about the items.
+		b.WriteString("array")
+	default:
+		b.WriteByte('[')
+		s.items.render(b, indent)
+		b.WriteString("...]")
+	}
+}
+
+// stringKeyword returns the keyword for the strings, detecting the known formats.
+func (s *shape) stringKeyword() string {
+	for i, f := range formats {
+		if s.formats[i] == s.strings {
+			return f.keyword
+		}
+	}
+	return "string"
+}
+
+// renderObject writes the pattern for the objects with every property on a separate line.
+func (s *shape) renderObject(b *strings.Builder, indent string) {
+	if len(s.keys) == 0 {
+		b.WriteString("{}")
+		return
+	}
+	// Keys are written in the pattern as is, without escape sequences.
+	for _, key := range s.keys {
+		if strings.ContainsAny(key, "\"\n") {
+			b.WriteString("object")
+			return
+		}
+	}
+	inner := indent + inferIndent
+	b.WriteString("{\n")
+	for i, key := range s.keys {
+		prop := s.props[key]
+		b.WriteString(inner)
+		// A key starting with "^" would be a regular expression.
+		if strings.HasPrefix(key, "^") {
+			key = "^" + key
+		}
+		b.WriteString(`"` + key + `"`)
+		if prop.count() < s.objects {
+			b.WriteByte('?')
+		}
+		b.WriteString(": ")
+		prop.render(b, inner)
+		if i < len(s.keys)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString(indent + "}")
+}
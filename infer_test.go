@@ -0,0 +1,94 @@
+package testo_test
+
+import (
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+func TestInfer(t *testing.T) {
+	samples := [][]byte{
+		[]byte(`{
+			"id": "0b6e6b2e-1c2e-4c4b-9b0a-0b6e6b2e1c2e",
+			"name": "aragorn",
+			"age": 87,
+			"created_at": "2024-03-15T12:00:00Z",
+			"birthday": "2931-03-01",
+			"score": 1.5,
+			"tags": ["king", "ranger"],
+			"friends": [{"name": "legolas", "age": 2931}],
+			"meta": {},
+			"history": [],
+			"extra": null
+		}`),
+		[]byte(`{
+			"id": "6f1c9a3e-2d4b-4f6a-8c1d-3e5f7a9b0c2d",
+			"name": "frodo",
+			"nickname": "ring-bearer",
+			"age": 50,
+			"created_at": "2024-03-16T08:30:00+02:00",
+			"birthday": "2968-09-22",
+			"score": 2,
+			"tags": [],
+			"friends": [{"name": "sam"}, {"name": "pippin", "age": 28}],
+			"meta": {},
+			"history": [],
+			"extra": 1
+		}`),
+		[]byte(`not json`),
+	}
+	want := `{
+    "age": int,
+    "birthday": date,
+    "created_at": datetime,
+    "extra": any,
+    "friends": [{
+        "age"?: int,
+        "name": string
+    }...],
+    "history": array,
+    "id": uuid,
+    "meta": {},
+    "name": string,
+    "score": float,
+    "tags": [string...],
+    "nickname"?: string
+}`
+	got := testo.Infer(samples...)
+	if got != want {
+		t.Fatalf("unexpected pattern:\n%s", got)
+	}
+	for _, sample := range samples[:2] {
+		testo.Assert(t, sample, got)
+	}
+}
+
+func TestInfer_Scalars(t *testing.T) {
+	tests := []struct {
+		samples []string
+		want    string
+	}{
+		{[]string{`1`, `2`}, `int`},
+		{[]string{`1`, `2.5`}, `float`},
+		{[]string{`"a"`, `"0b6e6b2e-1c2e-4c4b-9b0a-0b6e6b2e1c2e"`}, `string`},
+		{[]string{`true`}, `bool`},
+		{[]string{`null`}, `null`},
+		{[]string{`1`, `"a"`}, `any`},
+		{[]string{`[[1], [2, 3]]`}, `[[int...]...]`},
+		{[]string{`{"^id": 1}`}, "{\n    \"^^id\": int\n}"},
+		{nil, `any`},
+	}
+	for _, tt := range tests {
+		samples := make([][]byte, 0, len(tt.samples))
+		for _, s := range tt.samples {
+			samples = append(samples, []byte(s))
+		}
+		got := testo.Infer(samples...)
+		if got != tt.want {
+			t.Fatalf("unexpected pattern for %v: %s", tt.samples, got)
+		}
+		for _, sample := range samples {
+			testo.Assert(t, sample, got)
+		}
+	}
+}
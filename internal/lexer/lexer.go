@@ -1,5 +1,10 @@
 package lexer
 
+import (
+	"slices"
+	"strings"
+)
+
 // Lexer tokenizes input string for parsing.
 type Lexer struct {
 	input        string // The input being tokenized.
@@ -41,23 +46,68 @@ func (l *Lexer) readChar() {
 }
 
 // NextToken extracts the next token from the input.
+//
+// The position of the token points to its first character.
 func (l *Lexer) NextToken() Token {
-	var tok Token
-
 	l.skipWhitespace()
+	line, column, offset := l.line, l.column, min(l.position, len(l.input))
+	tok := l.readToken()
+	tok.Line = line
+	tok.Column = column
+	tok.Offset = offset
+	tok.End = min(l.position, len(l.input))
+	return tok
+}
+
+// Input returns the text being tokenized.
+func (l *Lexer) Input() string {
+	return l.input
+}
+
+// readToken reads the token starting at the current character.
+func (l *Lexer) readToken() Token {
+	var tok Token
 
 	switch l.ch {
-	case '{', '}', '[', ']', ':', ',':
+	case '{', '}', '[', ']', ':', ',', '(', ')', '=', '?':
 		tok = l.makeSingleCharToken()
 	case '"':
 		tok = l.readString()
+	case '/':
+		tok = l.readRegex()
+	case '.':
+		if l.peekChar(1) == '.' && l.peekChar(2) == '.' {
+			tok = l.newToken(ELLIPSIS, "...")
+			l.readChar()
+			l.readChar()
+		} else if l.peekChar(1) == '.' {
+			tok = l.newToken(DOTDOT, "..")
+			l.readChar()
+		} else {
+			tok = l.newToken(ILLEGAL, string(l.ch))
+		}
+	case '>', '<':
+		tok = l.readComparison()
+	case '$':
+		if !isLetter(l.peekChar(1)) {
+			tok = l.newToken(ILLEGAL, string(l.ch))
+			break
+		}
+		l.readChar()
+		ident := l.readIdentifier()
+		ident.Type = VARIABLE
+		return ident
 	case 0:
 		tok = l.newToken(EOF, "")
 	default:
 		if isLetter(l.ch) {
 			return l.readIdentifier()
-		} else if isDigit(l.ch) {
-			return l.newToken(NUMBER, l.readNumber())
+		} else if IsDigit(l.ch) || (l.ch == '-' && IsDigit(l.peekChar(1))) {
+			number := l.readNumber()
+			if isLetter(l.ch) {
+				return l.readDuration(number)
+			}
+			return l.newToken(NUMBER, number)
 		} else {
 			tok = l.newToken(ILLEGAL, string(l.ch))
 		}
@@ -104,27 +154,117 @@ func (l *Lexer) readString() Token {
 	return l.newToken(STRING, l.input[start:l.position])
 }
 
+// peekChar returns the character the given number of positions ahead without consuming it.
+func (l *Lexer) peekChar(offset int) byte {
+	pos := l.position + offset
+	if pos >= len(l.input) {
+		return 0
+	}
+	return l.input[pos]
+}
+
+// readRegex reads a regular expression literal enclosed in slashes, like `/^[a-z]+$/`.
+//
+// A slash inside of the regex can be escaped with a backslash.
+func (l *Lexer) readRegex() Token {
+	startLine, startColumn := l.line, l.column
+	var rex strings.Builder
+
+	for {
+		l.readChar()
+		if l.ch == '/' || l.ch == 0 {
+			break
+		}
+		if l.ch == '\\' && l.peekChar(1) == '/' {
+			l.readChar()
+		}
+		rex.WriteByte(l.ch)
+	}
+
+	if l.ch == 0 {
+		return Token{
+			Type:    ILLEGAL,
+			Literal: "Unterminated regex",
+			Line:    startLine,
+			Column:  startColumn,
+		}
+	}
+
+	return l.newToken(REGEX, rex.String())
+}
+
 // readNumber reads a numeric literal.
+//
+// The syntax is the same as for JSON numbers: an optional minus sign,
+// the integer part, an optional fraction, and an optional exponent.
 func (l *Lexer) readNumber() string {
 	start := l.position
-	decimalSeen := false
-
-	for isDigit(l.ch) || (l.ch == '.' && !decimalSeen) {
-		if l.ch == '.' {
-			decimalSeen = true
-		}
+	if l.ch == '-' {
 		l.readChar()
 	}
+	l.readDigits()
+	if l.ch == '.' && IsDigit(l.peekChar(1)) {
+		l.readChar()
+		l.readDigits()
+	}
+	if l.ch == 'e' || l.ch == 'E' {
+		next := l.peekChar(1)
+		if IsDigit(next) {
+			l.readChar()
+			l.readDigits()
+		} else if (next == '+' || next == '-') && IsDigit(l.peekChar(2)) {
+			l.readChar()
+			l.readChar()
+			l.readDigits()
+		}
+	}
 	return l.input[start:l.position]
 }
 
+// readDigits skips over a sequence of decimal digits.
+func (l *Lexer) readDigits() {
+	for IsDigit(l.ch) {
+		l.readChar()
+	}
+}
+
+// readDuration reads the unit of a duration literal, like `5s` or `1h30m`.
+//
+// The numeric part of the literal is already consumed.
+func (l *Lexer) readDuration(number string) Token {
+	start := l.position
+	for isLetter(l.ch) || IsDigit(l.ch) || l.ch == '.' {
+		l.readChar()
+	}
+	return l.newToken(DURATION, number+l.input[start:l.position])
+}
+
+// readComparison reads a comparison operator: `>`, `>=`, `<`, or `<=`.
+func (l *Lexer) readComparison() Token {
+	if l.peekChar(1) != '=' {
+		if l.ch == '>' {
+			return l.newToken(GT, ">")
+		}
+		return l.newToken(LT, "<")
+	}
+	ch := l.ch
+	l.readChar()
+	if ch == '>' {
+		return l.newToken(GTE, ">=")
+	}
+	return l.newToken(LTE, "<=")
+}
+
 // readIdentifier reads an identifier or keyword and returns the appropriate token.
 func (l *Lexer) readIdentifier() Token {
 	start := l.position
-	for isLetter(l.ch) {
+	for isLetter(l.ch) || IsDigit(l.ch) {
 		l.readChar()
 	}
 	ident := l.input[start:l.position]
+	if l.ch == '"' && isStringMode(ident) {
+		return l.newToken(STRING_MODE, ident)
+	}
 	tokenType := lookupKeyword(ident)
 	return l.newToken(tokenType, ident)
 }
@@ -154,13 +294,21 @@ func singleCharTokenType(ch byte) TokenType {
 		return COLON
 	case ',':
 		return COMMA
+	case '(':
+		return LPAREN
+	case ')':
+		return RPAREN
+	case '=':
+		return ASSIGN
+	case '?':
+		return QUESTION
 	default:
 		return ILLEGAL
 	}
 }
 
-// isDigit checks if a character is a digit.
-func isDigit(ch byte) bool {
+// IsDigit checks if a character is a digit.
+func IsDigit(ch byte) bool {
 	return '0' <= ch && ch <= '9'
 }
 
@@ -169,44 +317,116 @@ func isLetter(ch byte) bool {
 	return ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
 }
 
-// lookupKeyword determines if an identifier matches a keyword.
-func lookupKeyword(ident string) TokenType {
+// isStringMode checks if the identifier can be used as a prefix of a string literal, like `i"hello"`.
+func isStringMode(ident string) bool {
 	switch ident {
-	case "true":
-		return TRUE
-	case "false":
-		return FALSE
-	case "null", "nil", "none":
-		return NULL
-	case "any":
-		return TYPE_ANY
-	case "string", "str":
-		return TYPE_STRING
-	case "int", "integer":
-		return TYPE_INT
-	case "uint":
-		return TYPE_UINT
-	case "float", "float64", "number", "f64":
-		return TYPE_FLOAT
-	case "bool", "boolean":
-		return TYPE_BOOL
-	case "obj", "object", "struct", "map":
-		return TYPE_OBJECT
-	case "arr", "array", "slice", "list":
-		return TYPE_ARRAY
-	case "strings", "strs":
-		return TYPE_STRINGS
-	case "ints", "integers":
-		return TYPE_INTS
-	case "uints":
-		return TYPE_UINTS
-	case "floats", "numbers":
-		return TYPE_FLOATS
-	case "bools", "booleans":
-		return TYPE_BOOLS
-	case "objs", "objects", "structs", "maps":
-		return TYPE_OBJECTS
+	case "i", "glob", "trim", "nfc":
+		return true
 	default:
-		return ILLEGAL
+		return false
+	}
+}
+
+// keywords maps keywords, including aliases, to their token types.
+var keywords = map[string]TokenType{
+	"true":     TRUE,
+	"false":    FALSE,
+	"null":     NULL,
+	"nil":      NULL,
+	"none":     NULL,
+	"any":      TYPE_ANY,
+	"string":   TYPE_STRING,
+	"str":      TYPE_STRING,
+	"int":      TYPE_INT,
+	"integer":  TYPE_INT,
+	"uint":     TYPE_UINT,
+	"i8":       TYPE_I8,
+	"int8":     TYPE_I8,
+	"i16":      TYPE_I16,
+	"int16":    TYPE_I16,
+	"i32":      TYPE_I32,
+	"int32":    TYPE_I32,
+	"i64":      TYPE_I64,
+	"int64":    TYPE_I64,
+	"u8":       TYPE_U8,
+	"uint8":    TYPE_U8,
+	"u16":      TYPE_U16,
+	"uint16":   TYPE_U16,
+	"u32":      TYPE_U32,
+	"uint32":   TYPE_U32,
+	"u64":      TYPE_U64,
+	"uint64":   TYPE_U64,
+	"safeint":  TYPE_SAFEINT,
+	"float":    TYPE_FLOAT,
+	"float64":  TYPE_FLOAT,
+	"number":   TYPE_FLOAT,
+	"f64":      TYPE_FLOAT,
+	"bool":     TYPE_BOOL,
+	"boolean":  TYPE_BOOL,
+	"obj":      TYPE_OBJECT,
+	"object":   TYPE_OBJECT,
+	"struct":   TYPE_OBJECT,
+	"map":      TYPE_OBJECT,
+	"arr":      TYPE_ARRAY,
+	"array":    TYPE_ARRAY,
+	"slice":    TYPE_ARRAY,
+	"list":     TYPE_ARRAY,
+	"numstr":   TYPE_NUMSTR,
+	"floatstr": TYPE_FLOATSTR,
+	"intstr":   TYPE_INTSTR,
+	"boolstr":  TYPE_BOOLSTR,
+	"uuid":     TYPE_UUID,
+	"datetime": TYPE_DATETIME,
+	"date":     TYPE_DATE,
+	"json":     TYPE_JSON,
+	"base64":   TYPE_BASE64,
+	"jwt":      TYPE_JWT,
+	"url":      TYPE_URL,
+	"uri":      TYPE_URL,
+	"strings":  TYPE_STRINGS,
+	"strs":     TYPE_STRINGS,
+	"ints":     TYPE_INTS,
+	"integers": TYPE_INTS,
+	"uints":    TYPE_UINTS,
+	"floats":   TYPE_FLOATS,
+	"numbers":  TYPE_FLOATS,
+	"bools":    TYPE_BOOLS,
+	"booleans": TYPE_BOOLS,
+	"objs":     TYPE_OBJECTS,
+	"objects":  TYPE_OBJECTS,
+	"structs":  TYPE_OBJECTS,
+	"maps":     TYPE_OBJECTS,
+}
+
+// lookupKeyword returns the token type of the keyword or IDENT if the identifier isn't a keyword.
+func lookupKeyword(ident string) TokenType {
+	tokenType, found := keywords[ident]
+	if !found {
+		return IDENT
+	}
+	return tokenType
+}
+
+// IsIdentifier checks if the name can be used as an identifier, that is,
+// it consists of ASCII letters and digits, starts with a letter, and isn't a keyword.
+func IsIdentifier(name string) bool {
+	if name == "" || !isLetter(name[0]) {
+		return false
+	}
+	for i := range len(name) {
+		if !isLetter(name[i]) && !IsDigit(name[i]) {
+			return false
+		}
+	}
+	return lookupKeyword(name) == IDENT
+}
+
+// Keywords returns all keywords, including aliases, in alphabetical order.
+func Keywords() []string {
+	res := make([]string, 0, len(keywords))
+	for keyword := range keywords {
+		res = append(res, keyword)
 	}
+	slices.Sort(res)
+	return res
 }
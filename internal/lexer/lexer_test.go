@@ -162,3 +162,99 @@ func TestNextToken_ValidCharacters(t *testing.T) {
 		}
 	}
 }
+
+func TestNextToken_Numbers(t *testing.T) {
+	input := `0 -13 3.14 -0.5 1e10 2E-3 6.02e+23 1.2.3 -x`
+	expected := []struct {
+		expectedType    lexer.TokenType
+		expectedLiteral string
+	}{
+		{lexer.NUMBER, "0"},
+		{lexer.NUMBER, "-13"},
+		{lexer.NUMBER, "3.14"},
+		{lexer.NUMBER, "-0.5"},
+		{lexer.NUMBER, "1e10"},
+		{lexer.NUMBER, "2E-3"},
+		{lexer.NUMBER, "6.02e+23"},
+		{lexer.NUMBER, "1.2"},
+		{lexer.ILLEGAL, "."},
+		{lexer.NUMBER, "3"},
+		{lexer.ILLEGAL, "-"},
+		{lexer.IDENT, "x"},
+		{lexer.EOF, ""},
+	}
+	l := lexer.New(input)
+	for i, tt := range expected {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf(
+				"tests[%d] - expected=%s %q, got=%s %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal,
+			)
+		}
+	}
+}
+
+func TestNextToken_RegexAndRange(t *testing.T) {
+	input := `map[/^a\/b$/, 1..10] /x`
+	expected := []struct {
+		expectedType    lexer.TokenType
+		expectedLiteral string
+	}{
+		{lexer.TYPE_OBJECT, "map"},
+		{lexer.LBRACKET, "["},
+		{lexer.REGEX, "^a/b$"},
+		{lexer.COMMA, ","},
+		{lexer.NUMBER, "1"},
+		{lexer.DOTDOT, ".."},
+		{lexer.NUMBER, "10"},
+		{lexer.RBRACKET, "]"},
+		{lexer.ILLEGAL, "Unterminated regex"},
+		{lexer.EOF, ""},
+	}
+	l := lexer.New(input)
+	for i, tt := range expected {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf(
+				"tests[%d] - expected=%s %q, got=%s %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal,
+			)
+		}
+	}
+}
+
+func TestNextToken_Positions(t *testing.T) {
+	input := "{\n  \"age\": int(1..10),\n  $x\n}"
+	expected := []struct {
+		literal string
+		line    int
+		column  int
+		text    string
+	}{
+		{"{", 1, 1, "{"},
+		{"age", 2, 3, `"age"`},
+		{":", 2, 8, ":"},
+		{"int", 2, 10, "int"},
+		{"(", 2, 13, "("},
+		{"1", 2, 14, "1"},
+		{"..", 2, 15, ".."},
+		{"10", 2, 17, "10"},
+		{")", 2, 19, ")"},
+		{",", 2, 20, ","},
+		{"x", 3, 3, "$x"},
+		{"}", 4, 1, "}"},
+		{"", 4, 2, ""},
+	}
+	l := lexer.New(input)
+	for i, tt := range expected {
+		tok := l.NextToken()
+		text := input[tok.Offset:tok.End]
+		if tok.Literal != tt.literal || tok.Line != tt.line || tok.Column != tt.column || text != tt.text {
+			t.Fatalf(
+				"tests[%d] - expected=%q at %d:%d (%q), got=%q at %d:%d (%q)",
+				i, tt.literal, tt.line, tt.column, tt.text, tok.Literal, tok.Line, tok.Column, text,
+			)
+		}
+	}
+}
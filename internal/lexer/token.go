@@ -14,9 +14,26 @@ const (
 	RBRACKET TokenType = "]"
 	COLON    TokenType = ":"
 	COMMA    TokenType = ","
+	LPAREN   TokenType = "("
+	RPAREN   TokenType = ")"
+	DOTDOT   TokenType = ".."
+	ELLIPSIS TokenType = "..."
+	GT       TokenType = ">"
+	GTE      TokenType = ">="
+	LT       TokenType = "<"
+	LTE      TokenType = "<="
+	ASSIGN   TokenType = "="
+	QUESTION TokenType = "?"
 
 	STRING TokenType = "STRING"
 	NUMBER TokenType = "NUMBER"
+	REGEX  TokenType = "REGEX"
+
+	STRING_MODE TokenType = "STRING_MODE" // A prefix of a string literal, like `i` in `i"hello"`
+
+	IDENT    TokenType = "IDENT"    // An identifier that isn't a keyword, like `now`
+	VARIABLE TokenType = "VARIABLE" // A reference to a variable, like `$start`
+	DURATION TokenType = "DURATION" // A duration literal, like `5s`
 
 	TRUE  TokenType = "TRUE"
 	FALSE TokenType = "FALSE"
@@ -31,6 +48,30 @@ const (
 	TYPE_OBJECT TokenType = "MATCH_OBJECT"
 	TYPE_ARRAY  TokenType = "MATCH_ARRAY"
 
+	TYPE_I8      TokenType = "MATCH_I8"
+	TYPE_I16     TokenType = "MATCH_I16"
+	TYPE_I32     TokenType = "MATCH_I32"
+	TYPE_I64     TokenType = "MATCH_I64"
+	TYPE_U8      TokenType = "MATCH_U8"
+	TYPE_U16     TokenType = "MATCH_U16"
+	TYPE_U32     TokenType = "MATCH_U32"
+	TYPE_U64     TokenType = "MATCH_U64"
+	TYPE_SAFEINT TokenType = "MATCH_SAFEINT"
+
+	TYPE_NUMSTR   TokenType = "MATCH_NUMSTR"
+	TYPE_FLOATSTR TokenType = "MATCH_FLOATSTR"
+	TYPE_INTSTR   TokenType = "MATCH_INTSTR"
+	TYPE_BOOLSTR  TokenType = "MATCH_BOOLSTR"
+
+	TYPE_UUID     TokenType = "MATCH_UUID"
+	TYPE_DATETIME TokenType = "MATCH_DATETIME"
+	TYPE_DATE     TokenType = "MATCH_DATE"
+
+	TYPE_JSON   TokenType = "MATCH_JSON"
+	TYPE_BASE64 TokenType = "MATCH_BASE64"
+	TYPE_JWT    TokenType = "MATCH_JWT"
+	TYPE_URL    TokenType = "MATCH_URL"
+
 	TYPE_STRINGS TokenType = "MATCH_STRINGS"
 	TYPE_BOOLS   TokenType = "MATCH_BOOLS"
 	TYPE_INTS    TokenType = "MATCH_INTS"
@@ -45,4 +86,6 @@ type Token struct {
 	Literal string    // The literal value of the token
 	Line    int       // Line number where the token appears
 	Column  int       // Column number where the token appears
+	Offset  int       // Byte offset of the first character of the token
+	End     int       // Byte offset right after the last character of the token
 }
@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// arrayType is an array with all items matching the given validator.
+//
+// Unlike [valdo.ArrayType], it reports errors for all items, not only the first one.
+type arrayType struct {
+	item valdo.Validator
+}
+
+// Validate implements [valdo.Validator].
+func (a arrayType) Validate(data any) valdo.Error {
+	d, ok := data.([]any)
+	if !ok || d == nil {
+		return valdo.ErrType{Got: typeName(data), Expected: "array"}
+	}
+	res := valdo.Errors{}
+	for i, val := range d {
+		res.Add(validateItem(i, a.item, val))
+	}
+	return res.Flatten()
+}
+
+// Schema implements [valdo.Validator].
+func (a arrayType) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("array")},
+	}
+	items := a.item.Schema()
+	if len(items) > 0 {
+		res = append(res, jsony.Field{K: "items", V: items})
+	}
+	return res
+}
+
+// tupleType is an array with every item matching the validator at the same position.
+//
+// Unlike [valdo.TupleType], it reports errors for all items, not only the first one,
+// and validates the items even if the array has a wrong length.
+type tupleType struct {
+	items []valdo.Validator
+	// If true, the items can be in any order.
+	unordered bool
+	// Muted while trying the items of an unordered array. Nil if nothing is captured.
+	captures *captures
+}
+
+// Validate implements [valdo.Validator].
+func (t tupleType) Validate(data any) valdo.Error {
+	d, ok := data.([]any)
+	if !ok || d == nil {
+		return valdo.ErrType{Got: typeName(data), Expected: "array"}
+	}
+	res := valdo.Errors{}
+	if len(d) < len(t.items) {
+		res.Add(valdo.ErrMinItems{Value: len(t.items)})
+	}
+	if len(d) > len(t.items) {
+		res.Add(valdo.ErrMaxItems{Value: len(t.items)})
+	}
+	if t.unordered {
+		t.validateUnordered(d, &res)
+		return res.Flatten()
+	}
+	for i, val := range d[:min(len(d), len(t.items))] {
+		res.Add(validateItem(i, t.items[i], val))
+	}
+	return res.Flatten()
+}
+
+// validateUnordered pairs every validator with a distinct array item it matches.
+//
+// The pairs are found as a maximum bipartite matching. Validators and items
+// left without a pair are validated against each other in order, so that
+// the errors describe why the leftover items don't match.
+//
+// Captures are muted while trying all pairs, and only the chosen pairs
+// are validated again to capture the values.
+func (t tupleType) validateUnordered(d []any, res *valdo.Errors) {
+	if t.captures != nil {
+		t.captures.muted++
+	}
+	matches := make([][]bool, len(t.items))
+	for i, v := range t.items {
+		matches[i] = make([]bool, len(d))
+		for j, val := range d {
+			matches[i][j] = v.Validate(val) == nil
+		}
+	}
+	if t.captures != nil {
+		t.captures.muted--
+	}
+	// owners[j] is the validator paired with the item j, or -1.
+	owners := make([]int, len(d))
+	for j := range owners {
+		owners[j] = -1
+	}
+	var pair func(i int, seen []bool) bool
+	pair = func(i int, seen []bool) bool {
+		for j := range d {
+			if !matches[i][j] || seen[j] {
+				continue
+			}
+			seen[j] = true
+			if owners[j] == -1 || pair(owners[j], seen) {
+				owners[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	paired := make([]bool, len(t.items))
+	for i := range t.items {
+		paired[i] = pair(i, make([]bool, len(d)))
+	}
+	if t.captures != nil {
+		for j, owner := range owners {
+			if owner != -1 {
+				t.items[owner].Validate(d[j])
+			}
+		}
+	}
+
+	validators := make([]int, 0)
+	for i, ok := range paired {
+		if !ok {
+			validators = append(validators, i)
+		}
+	}
+	items := make([]int, 0)
+	for j, owner := range owners {
+		if owner == -1 {
+			items = append(items, j)
+		}
+	}
+	for k := range min(len(validators), len(items)) {
+		j := items[k]
+		res.Add(validateItem(j, t.items[validators[k]], d[j]))
+	}
+}
+
+// Schema implements [valdo.Validator].
+func (t tupleType) Schema() jsony.Object {
+	if t.unordered {
+		return jsony.Object{
+			jsony.Field{K: "type", V: jsony.SafeString("array")},
+			jsony.Field{K: "minItems", V: jsony.Int(len(t.items))},
+			jsony.Field{K: "maxItems", V: jsony.Int(len(t.items))},
+		}
+	}
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("array")},
+		jsony.Field{K: "items", V: jsony.Bool(false)},
+	}
+	if len(t.items) > 0 {
+		items := make([]jsony.Object, len(t.items))
+		for i, validator := range t.items {
+			items[i] = validator.Schema()
+		}
+		res = append(res, jsony.Field{K: "prefixItems", V: jsony.Array[jsony.Object](items)})
+	}
+	return res
+}
+
+// validateItem validates the array item at the given index.
+func validateItem(index int, v valdo.Validator, val any) valdo.Error {
+	err := v.Validate(val)
+	if err != nil {
+		return valdo.ErrIndex{Index: index, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/regexes"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// DecodeJSON parses the given JSON message.
+//
+// Numbers are decoded as [json.Number] instead of float64,
+// so that big integers and long decimals are validated exactly.
+func DecodeJSON(raw []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var parsed any
+	err := decoder.Decode(&parsed)
+	if err != nil {
+		return nil, err
+	}
+	_, err = decoder.Token()
+	if err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return parsed, nil
+}
+
+// jsonType is a string containing serialized JSON.
+//
+// If the inner validator is not nil, it's used to validate the decoded value.
+type jsonType struct {
+	inner valdo.Validator
+}
+
+// Validate implements [valdo.Validator].
+func (v jsonType) Validate(data any) valdo.Error {
+	raw, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	parsed, err := DecodeJSON([]byte(raw))
+	if err != nil {
+		return ErrMalformed{Kind: "json", Err: err}
+	}
+	if v.inner == nil {
+		return nil
+	}
+	vErr := v.inner.Validate(parsed)
+	if vErr != nil {
+		return ErrDecoded{Kind: "json", Err: vErr}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v jsonType) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+		jsony.Field{K: "contentMediaType", V: jsony.SafeString("application/json")},
+	}
+	if v.inner != nil {
+		res = append(res, jsony.Field{K: "contentSchema", V: v.inner.Schema()})
+	}
+	return res
+}
+
+// base64Type is a string containing base64-encoded data.
+//
+// If the inner validator is not nil, it's used to validate the decoded data as a string.
+type base64Type struct {
+	inner valdo.Validator
+}
+
+// Validate implements [valdo.Validator].
+func (v base64Type) Validate(data any) valdo.Error {
+	raw, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	decoded, err := decodeBase64(raw)
+	if err != nil {
+		return ErrMalformed{Kind: "base64", Err: err}
+	}
+	if v.inner == nil {
+		return nil
+	}
+	vErr := v.inner.Validate(string(decoded))
+	if vErr != nil {
+		return ErrDecoded{Kind: "base64", Err: vErr}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v base64Type) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+		jsony.Field{K: "contentEncoding", V: jsony.SafeString("base64")},
+	}
+	if v.inner != nil {
+		res = append(res, jsony.Field{K: "contentSchema", V: v.inner.Schema()})
+	}
+	return res
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding.
+func decodeBase64(raw string) ([]byte, error) {
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(raw, "-_") {
+		encoding = base64.URLEncoding
+	}
+	if !strings.HasSuffix(raw, "=") {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	return encoding.DecodeString(raw)
+}
+
+// jwtType is a string containing a JSON Web Token.
+//
+// The token is decoded but not verified. If the validators are not nil,
+// they are used to validate the decoded header and claims.
+type jwtType struct {
+	header valdo.Validator
+	claims valdo.Validator
+}
+
+// Validate implements [valdo.Validator].
+func (v jwtType) Validate(data any) valdo.Error {
+	raw, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return ErrMalformed{Kind: "jwt", Err: errors.New("expected 3 parts separated by dots")}
+	}
+	header, err := decodeJWTPart(parts[0])
+	if err != nil {
+		return ErrMalformed{Kind: "jwt", Err: fmt.Errorf("header: %w", err)}
+	}
+	claims, err := decodeJWTPart(parts[1])
+	if err != nil {
+		return ErrMalformed{Kind: "jwt", Err: fmt.Errorf("claims: %w", err)}
+	}
+	res := valdo.Errors{}
+	if v.header != nil {
+		vErr := v.header.Validate(header)
+		if vErr != nil {
+			res.Add(ErrDecoded{Kind: "jwt-header", Err: vErr})
+		}
+	}
+	if v.claims != nil {
+		vErr := v.claims.Validate(claims)
+		if vErr != nil {
+			res.Add(ErrDecoded{Kind: "jwt", Err: vErr})
+		}
+	}
+	return res.Flatten()
+}
+
+// Schema implements [valdo.Validator].
+func (v jwtType) Schema() jsony.Object {
+	return jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+		jsony.Field{K: "pattern", V: jsony.String(regexes.JWT)},
+	}
+}
+
+// decodeJWTPart decodes a base64url-encoded JSON object from a JWT.
+func decodeJWTPart(raw string) (any, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(raw, "="))
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := DecodeJSON(decoded)
+	if err != nil {
+		return nil, err
+	}
+	_, isObject := parsed.(map[string]any)
+	if !isObject {
+		return nil, errors.New("expected a JSON object")
+	}
+	return parsed, nil
+}
+
+// urlType is a string containing an absolute URL.
+//
+// If the inner validator is not nil, it's used to validate the URL components.
+// See [decomposeURL] for the structure of the components.
+type urlType struct {
+	inner valdo.Validator
+}
+
+// Validate implements [valdo.Validator].
+func (v urlType) Validate(data any) valdo.Error {
+	raw, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ErrMalformed{Kind: "url", Err: err}
+	}
+	if parsed.Scheme == "" {
+		return ErrMalformed{Kind: "url", Err: errors.New("missing scheme")}
+	}
+	if v.inner == nil {
+		return nil
+	}
+	vErr := v.inner.Validate(decomposeURL(parsed))
+	if vErr != nil {
+		return ErrDecoded{Kind: "url", Err: vErr}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v urlType) Schema() jsony.Object {
+	return jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+		jsony.Field{K: "format", V: jsony.SafeString("uri")},
+	}
+}
+
+// decomposeURL converts the URL into an object with its components.
+//
+// The components are "scheme", "user", "password", "host", "port", "path",
+// "query", and "fragment". All components are strings except "query",
+// which is an object mapping each parameter to its value or,
+// if the parameter is repeated, to an array of its values.
+// Empty components are omitted.
+func decomposeURL(u *url.URL) map[string]any {
+	res := map[string]any{"scheme": u.Scheme}
+	if u.User != nil {
+		res["user"] = u.User.Username()
+		password, hasPassword := u.User.Password()
+		if hasPassword {
+			res["password"] = password
+		}
+	}
+	setIfNotEmpty := func(name, value string) {
+		if value != "" {
+			res[name] = value
+		}
+	}
+	setIfNotEmpty("host", u.Hostname())
+	setIfNotEmpty("port", u.Port())
+	setIfNotEmpty("path", u.Path)
+	setIfNotEmpty("fragment", u.Fragment)
+	values := u.Query()
+	if len(values) > 0 {
+		query := make(map[string]any, len(values))
+		for name, vals := range values {
+			if len(vals) == 1 {
+				query[name] = vals[0]
+				continue
+			}
+			items := make([]any, len(vals))
+			for i, val := range vals {
+				items[i] = val
+			}
+			query[name] = items
+		}
+		res["query"] = query
+	}
+	return res
+}
+
+// scalarString is a string containing a JSON scalar (a number, a boolean, or null),
+// like `"42"` or `"true"`.
+//
+// The decoded value is validated using the inner validator.
+type scalarString struct {
+	inner valdo.Validator
+}
+
+// Validate implements [valdo.Validator].
+func (v scalarString) Validate(data any) valdo.Error {
+	raw, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	if strings.TrimSpace(raw) != raw {
+		return ErrMalformed{Kind: "str", Err: errors.New("unexpected whitespace")}
+	}
+	parsed, err := DecodeJSON([]byte(raw))
+	if err != nil {
+		return ErrMalformed{Kind: "str", Err: err}
+	}
+	switch parsed.(type) {
+	case map[string]any, []any, string:
+		return ErrMalformed{Kind: "str", Err: fmt.Errorf("expected a number, a boolean, or null, got %s", typeName(parsed))}
+	}
+	vErr := v.inner.Validate(parsed)
+	if vErr != nil {
+		return ErrDecoded{Kind: "str", Err: vErr}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v scalarString) Schema() jsony.Object {
+	return jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+	}
+}
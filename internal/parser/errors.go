@@ -0,0 +1,307 @@
+package parser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+var (
+	_ valdo.ErrorWrapper = ErrDecoded{}
+	_ valdo.Error        = ErrMalformed{}
+)
+
+// An error in a value decoded from a string, like in `json(...)`.
+type ErrDecoded struct {
+	Format string
+	Kind   string
+	Err    valdo.Error
+}
+
+// GetDefault implements [valdo.Error] interface.
+func (e ErrDecoded) GetDefault() valdo.Error {
+	return ErrDecoded{}
+}
+
+// SetFormat implements [valdo.Error] interface.
+func (e ErrDecoded) SetFormat(f string) valdo.Error {
+	e.Format = f
+	return e
+}
+
+// Error implements [error] interface.
+func (e ErrDecoded) Error() string {
+	f := e.Format
+	if f == "" {
+		f = "({kind}) {error}"
+	}
+	r := strings.NewReplacer("{kind}", e.Kind, "{error}", e.Err.Error())
+	return r.Replace(f)
+}
+
+// Unwrap implements [valdo.ErrorWrapper] interface.
+func (e ErrDecoded) Unwrap() error {
+	return e.Err
+}
+
+// Map implements [valdo.ErrorWrapper] interface.
+func (e ErrDecoded) Map(f func(valdo.Error) valdo.Error) valdo.Error {
+	e.Err = f(e.Err)
+	return e
+}
+
+// An error indicating that a string cannot be decoded, like invalid JSON in `json(...)`.
+type ErrMalformed struct {
+	Format string
+	Kind   string
+	Err    error
+}
+
+// GetDefault implements [valdo.Error] interface.
+func (e ErrMalformed) GetDefault() valdo.Error {
+	return ErrMalformed{}
+}
+
+// SetFormat implements [valdo.Error] interface.
+func (e ErrMalformed) SetFormat(f string) valdo.Error {
+	e.Format = f
+	return e
+}
+
+// Error implements [error] interface.
+func (e ErrMalformed) Error() string {
+	f := e.Format
+	if f == "" {
+		f = "invalid {kind}: {error}"
+	}
+	r := strings.NewReplacer("{kind}", e.Kind, "{error}", e.Err.Error())
+	return r.Replace(f)
+}
+
+// Unwrap makes errors.Is and errors.As work for the decoding error.
+func (e ErrMalformed) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is a validation error that reports the path to every mismatched value.
+type ValidationError struct {
+	Err valdo.Error
+}
+
+// Error implements [error] interface.
+func (e ValidationError) Error() string {
+	lines := make([]string, 0)
+	for _, m := range e.Mismatches() {
+		location := FormatPath(m.Segments)
+		if location == "" {
+			lines = append(lines, m.Err.Error())
+		} else {
+			lines = append(lines, location+": "+m.Err.Error())
+		}
+	}
+	return strings.Join(lines, "; ")
+}
+
+// Unwrap makes errors.Is and errors.As work for the underlying valdo error.
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Mismatches returns every mismatched value in the order they were found.
+func (e ValidationError) Mismatches() []Mismatch {
+	res := make([]Mismatch, 0)
+	walk(e.Err, Mismatch{}, func(m Mismatch) {
+		res = append(res, m)
+	})
+	return res
+}
+
+// Mismatch is a single value that doesn't match the pattern.
+type Mismatch struct {
+	// The JSON Pointer to the value, built from the segments, like `/payload/a`.
+	//
+	// Values decoded from strings don't add reference tokens, so the pointer
+	// continues into the decoded value as if it replaced the string.
+	Path string
+	// The steps from the root of the input to the value.
+	Segments []Segment
+	// The part of the pattern the value was matched against.
+	Expected string
+	// The mismatched value. It's nil if the value is missing.
+	Actual any
+	// The position of the expected value in the pattern.
+	Line   int
+	Column int
+	// The error reported for the value.
+	Err valdo.Error
+}
+
+// Segment is a step on the path from the root of the input to a mismatched value.
+type Segment struct {
+	Kind SegmentKind
+	// The name of the property or the key, or the name of the decoder, like "json".
+	Name string
+	// The index of the array item.
+	Index int
+}
+
+// SegmentKind is the kind of a [Segment].
+type SegmentKind int
+
+const (
+	// A property of an object.
+	SegmentProperty SegmentKind = iota
+	// An item of an array.
+	SegmentIndex
+	// A key of an object that doesn't match the pattern, like in `map[uuid]int`.
+	SegmentKey
+	// A value decoded from a string, like in `json(...)`.
+	SegmentDecoded
+)
+
+// Pointer formats the segments as a JSON Pointer.
+//
+// Decoded values don't add reference tokens.
+func Pointer(segments []Segment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		switch s.Kind {
+		case SegmentProperty, SegmentKey:
+			b.WriteString("/" + escapePointer(s.Name))
+		case SegmentIndex:
+			b.WriteString("/" + strconv.Itoa(s.Index))
+		}
+	}
+	return b.String()
+}
+
+// Undecoded returns the segments up to the first decoded value.
+//
+// It's the path to the value as it's written in the input.
+func Undecoded(segments []Segment) []Segment {
+	for i, s := range segments {
+		if s.Kind == SegmentDecoded {
+			return segments[:i]
+		}
+	}
+	return segments
+}
+
+// FormatPath formats the segments for error messages.
+//
+// It's the JSON Pointer with the decoded values marked by the decoder name
+// in parentheses, like `/payload(json)/a` or `/(json)/a` at the root,
+// and errors in object keys marked with "(key)", like `/users/bob(key)`.
+func FormatPath(segments []Segment) string {
+	var b strings.Builder
+	for i, s := range segments {
+		switch s.Kind {
+		case SegmentProperty:
+			b.WriteString("/" + escapePointer(s.Name))
+		case SegmentIndex:
+			b.WriteString("/" + strconv.Itoa(s.Index))
+		case SegmentKey:
+			b.WriteString("/" + escapePointer(s.Name) + "(key)")
+		case SegmentDecoded:
+			if i == 0 {
+				b.WriteByte('/')
+			}
+			b.WriteString("(" + s.Name + ")")
+		}
+	}
+	return b.String()
+}
+
+// walk calls the callback for every leaf error with the information about the mismatched value.
+//
+// The state accumulates the path to the current error, the innermost position
+// in the pattern, and the value at the path if it's known.
+func walk(err valdo.Error, state Mismatch, f func(Mismatch)) {
+	switch e := err.(type) {
+	case valdo.Errors:
+		for _, sub := range e.Errs {
+			walk(sub, state, f)
+		}
+	case valdo.ErrProperty:
+		state.Actual = propertyOf(state.Actual, e.Name)
+		state.Segments = appendSegment(state.Segments, Segment{Kind: SegmentProperty, Name: e.Name})
+		walk(e.Err, state, f)
+	case valdo.ErrIndex:
+		state.Actual = itemOf(state.Actual, e.Index)
+		state.Segments = appendSegment(state.Segments, Segment{Kind: SegmentIndex, Index: e.Index})
+		walk(e.Err, state, f)
+	case valdo.ErrPropertyNames:
+		state.Actual = e.Name
+		state.Segments = appendSegment(state.Segments, Segment{Kind: SegmentKey, Name: e.Name})
+		walk(e.Err, state, f)
+	case ErrDecoded:
+		state.Segments = appendSegment(state.Segments, Segment{Kind: SegmentDecoded, Name: e.Kind})
+		walk(e.Err, state, f)
+	case ErrAt:
+		state.Actual = e.Got
+		state.Expected = e.Fragment
+		state.Line = e.Line
+		state.Column = e.Column
+		walk(e.Err, state, f)
+	case valdo.ErrRequired:
+		state.Actual = nil
+		state.Segments = appendSegment(state.Segments, Segment{Kind: SegmentProperty, Name: e.Name})
+		state.Err = err
+		emit(state, f)
+	case valdo.ErrType:
+		// Validators from valdo don't know about json.Number and other types
+		// supported by testo, so the type is detected again for the actual value.
+		if e.Got == "" {
+			e.Got = typeName(state.Actual)
+		}
+		state.Err = e
+		emit(state, f)
+	case valdo.ErrUnexpected:
+		state.Actual = propertyOf(state.Actual, e.Name)
+		state.Expected = ""
+		state.Segments = appendSegment(state.Segments, Segment{Kind: SegmentProperty, Name: e.Name})
+		state.Err = err
+		emit(state, f)
+	default:
+		state.Err = err
+		emit(state, f)
+	}
+}
+
+// appendSegment appends the segment to a copy of the path,
+// so that the sibling errors don't share the backing array.
+func appendSegment(segments []Segment, s Segment) []Segment {
+	return append(slices.Clip(segments), s)
+}
+
+// emit sets the JSON Pointer of the mismatch and passes it to the callback.
+func emit(m Mismatch, f func(Mismatch)) {
+	m.Path = Pointer(m.Segments)
+	f(m)
+}
+
+// propertyOf returns the value of the property if the data is an object.
+func propertyOf(data any, name string) any {
+	obj, ok := data.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return obj[name]
+}
+
+// itemOf returns the item at the given index if the data is an array.
+func itemOf(data any, index int) any {
+	arr, ok := data.([]any)
+	if !ok || index < 0 || index >= len(arr) {
+		return nil
+	}
+	return arr[index]
+}
+
+// escapePointer escapes a JSON Pointer reference token as described in RFC 6901.
+func escapePointer(name string) string {
+	name = strings.ReplaceAll(name, "~", "~0")
+	return strings.ReplaceAll(name, "/", "~1")
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+var _ valdo.ErrorWrapper = ErrAt{}
+
+// located is a validator that remembers where it is defined in the pattern.
+type located struct {
+	inner valdo.Validator
+	// The position of the first character of the value in the pattern.
+	line   int
+	column int
+	// The value as written in the pattern.
+	fragment string
+}
+
+// Validate implements [valdo.Validator].
+func (v located) Validate(data any) valdo.Error {
+	err := v.inner.Validate(data)
+	if err == nil {
+		return nil
+	}
+	return v.wrap(err, data)
+}
+
+// Schema implements [valdo.Validator].
+func (v located) Schema() jsony.Object {
+	return v.inner.Schema()
+}
+
+// wrap attaches the position of the value in the pattern to the error.
+func (v located) wrap(err valdo.Error, data any) ErrAt {
+	return ErrAt{
+		Err:      err,
+		Line:     v.line,
+		Column:   v.column,
+		Fragment: v.fragment,
+		Got:      data,
+	}
+}
+
+// locate attaches the position of the value in the pattern to the error, if it's known.
+//
+// It's used for errors that aren't produced by the validator itself,
+// like a missing required property.
+func locate(v valdo.Validator, err valdo.Error) valdo.Error {
+	loc, ok := v.(located)
+	if !ok {
+		return err
+	}
+	return loc.wrap(err, nil)
+}
+
+// ErrAt is an error produced by a value defined at the given position in the pattern.
+//
+// It's transparent: the error message is the same as of the wrapped error.
+type ErrAt struct {
+	Err valdo.Error
+	// The position of the first character of the value in the pattern.
+	Line   int
+	Column int
+	// The value as written in the pattern, like `int(1..10)`.
+	Fragment string
+	// The validated value.
+	Got any
+}
+
+// GetDefault implements [valdo.Error] interface.
+func (e ErrAt) GetDefault() valdo.Error {
+	return ErrAt{}
+}
+
+// SetFormat implements [valdo.Error] interface.
+//
+// The format is applied to the wrapped error.
+func (e ErrAt) SetFormat(f string) valdo.Error {
+	e.Err = e.Err.SetFormat(f)
+	return e
+}
+
+// Error implements [error] interface.
+func (e ErrAt) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap implements [valdo.ErrorWrapper] interface.
+func (e ErrAt) Unwrap() error {
+	return e.Err
+}
+
+// Map implements [valdo.ErrorWrapper] interface.
+func (e ErrAt) Map(f func(valdo.Error) valdo.Error) valdo.Error {
+	e.Err = f(e.Err)
+	return e
+}
@@ -0,0 +1,311 @@
+package parser
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// Bounds of the sized integer keywords.
+var (
+	minI8      = big.NewInt(math.MinInt8)
+	maxI8      = big.NewInt(math.MaxInt8)
+	minI16     = big.NewInt(math.MinInt16)
+	maxI16     = big.NewInt(math.MaxInt16)
+	minI32     = big.NewInt(math.MinInt32)
+	maxI32     = big.NewInt(math.MaxInt32)
+	minI64     = big.NewInt(math.MinInt64)
+	maxI64     = big.NewInt(math.MaxInt64)
+	maxU8      = big.NewInt(math.MaxUint8)
+	maxU16     = big.NewInt(math.MaxUint16)
+	maxU32     = big.NewInt(math.MaxUint32)
+	maxU64     = new(big.Int).SetUint64(math.MaxUint64)
+	minSafeInt = big.NewInt(-(1<<53 - 1))
+	maxSafeInt = big.NewInt(1<<53 - 1)
+	zero       = big.NewInt(0)
+)
+
+// intType is an integer number, optionally restricted to the given (inclusive) bounds.
+//
+// A nil bound means that the range is unbounded from that side.
+// Unlike [valdo.Int], it doesn't convert the value into a Go int,
+// so numbers of any size are checked exactly.
+type intType struct {
+	min *big.Int
+	max *big.Int
+	// If true, numbers written with a fraction or an exponent, like `13.0`, aren't integers.
+	strict bool
+}
+
+// Validate implements [valdo.Validator].
+func (v intType) Validate(data any) valdo.Error {
+	val, ok := ToRat(data)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "integer"}
+	}
+	if v.strict && isFloat(data) {
+		return valdo.ErrType{Got: "float", Expected: "integer"}
+	}
+	if !val.IsInt() {
+		return valdo.ErrType{Got: "number", Expected: "integer"}
+	}
+	num := val.Num()
+	if v.min != nil && num.Cmp(v.min) < 0 {
+		return valdo.ErrMin{Value: v.min}
+	}
+	if v.max != nil && num.Cmp(v.max) > 0 {
+		return valdo.ErrMax{Value: v.max}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v intType) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("integer")},
+	}
+	if v.min != nil {
+		res = append(res, jsony.Field{K: "minimum", V: rawNumber(v.min.String())})
+	}
+	if v.max != nil {
+		res = append(res, jsony.Field{K: "maximum", V: rawNumber(v.max.String())})
+	}
+	return res
+}
+
+// floatType is any number, integer or not, optionally restricted to the given (inclusive) bounds.
+//
+// A nil bound means that the range is unbounded from that side.
+type floatType struct {
+	min *big.Rat
+	max *big.Rat
+	// If true, numbers written without a fraction or an exponent, like `13`, aren't floats.
+	strict bool
+}
+
+// Validate implements [valdo.Validator].
+func (v floatType) Validate(data any) valdo.Error {
+	val, ok := ToRat(data)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "number"}
+	}
+	if v.strict && !isFloat(data) {
+		return valdo.ErrType{Got: "integer", Expected: "float"}
+	}
+	if v.min != nil && val.Cmp(v.min) < 0 {
+		return valdo.ErrMin{Value: formatRat(v.min)}
+	}
+	if v.max != nil && val.Cmp(v.max) > 0 {
+		return valdo.ErrMax{Value: formatRat(v.max)}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v floatType) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("number")},
+	}
+	if v.min != nil {
+		res = append(res, jsony.Field{K: "minimum", V: rawNumber(formatRat(v.min))})
+	}
+	if v.max != nil {
+		res = append(res, jsony.Field{K: "maximum", V: rawNumber(formatRat(v.max))})
+	}
+	return res
+}
+
+// numConst is a number exactly equal to the given value.
+//
+// The comparison is done on exact rationals, so `1e2` is equal to `100`
+// but `0.1000000000000000055` is not equal to `0.1`.
+type numConst struct {
+	value   *big.Rat
+	literal string
+	// If not nil, the maximum allowed absolute difference from the value.
+	tolerance *big.Rat
+	// If true, an integer doesn't match a number written with a fraction or an exponent, and vice versa.
+	strict bool
+}
+
+// Validate implements [valdo.Validator].
+func (v numConst) Validate(data any) valdo.Error {
+	val, ok := ToRat(data)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "number"}
+	}
+	if v.strict {
+		want := isFloat(json.Number(v.literal))
+		if got := isFloat(data); got != want {
+			return valdo.ErrType{Got: numberKind(got), Expected: numberKind(want)}
+		}
+	}
+	if v.tolerance != nil {
+		diff := new(big.Rat).Sub(val, v.value)
+		if diff.Abs(diff).Cmp(v.tolerance) > 0 {
+			return valdo.ErrConst{Got: data, Expected: v.literal + " ± " + formatRat(v.tolerance)}
+		}
+		return nil
+	}
+	if val.Cmp(v.value) != 0 {
+		return valdo.ErrConst{Got: data, Expected: v.literal}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v numConst) Schema() jsony.Object {
+	return jsony.Object{
+		jsony.Field{K: "const", V: rawNumber(v.literal)},
+	}
+}
+
+// rawNumber is a number that is written into JSON as is.
+type rawNumber string
+
+// EncodeJSON implements [jsony.Encoder].
+func (v rawNumber) EncodeJSON(w *jsony.Bytes) {
+	w.Extend([]byte(v))
+}
+
+// formatRat formats the rational as a decimal number.
+//
+// The rationals come from decimal literals, so they always have a finite decimal representation.
+// If the representation is too long, it's rounded.
+func formatRat(val *big.Rat) string {
+	if val.IsInt() {
+		return val.Num().String()
+	}
+	for prec := 1; prec < 100; prec++ {
+		res := val.FloatString(prec)
+		back, ok := new(big.Rat).SetString(res)
+		if ok && back.Cmp(val) == 0 {
+			return res
+		}
+	}
+	return val.FloatString(100)
+}
+
+// ToRat converts a number of any supported type into an exact rational.
+//
+// The second returned value is false if the value isn't a number.
+func ToRat(data any) (*big.Rat, bool) {
+	switch val := data.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(string(val))
+	case *big.Int:
+		if val == nil {
+			return nil, false
+		}
+		return new(big.Rat).SetInt(val), true
+	case *big.Rat:
+		if val == nil {
+			return nil, false
+		}
+		return new(big.Rat).Set(val), true
+	case *big.Float:
+		if val == nil || val.IsInf() {
+			return nil, false
+		}
+		r, _ := val.Rat(nil)
+		return r, true
+	case float64:
+		return floatToRat(val, 64)
+	case float32:
+		return floatToRat(float64(val), 32)
+	case int:
+		return new(big.Rat).SetInt64(int64(val)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(val)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(val)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(val)), true
+	case int64:
+		return new(big.Rat).SetInt64(val), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(val)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(val)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(val)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(val)), true
+	case uint64:
+		return new(big.Rat).SetUint64(val), true
+	default:
+		return nil, false
+	}
+}
+
+// isFloat checks if the number is a Go floating point number
+// or is written with a fraction or an exponent, like `13.0` or `1e2`.
+func isFloat(data any) bool {
+	switch val := data.(type) {
+	case json.Number:
+		return strings.ContainsAny(string(val), ".eE")
+	case float32, float64, *big.Float, *big.Rat:
+		return true
+	default:
+		return false
+	}
+}
+
+// numberKind returns the name of the kind of number for error messages.
+func numberKind(float bool) string {
+	if float {
+		return "float"
+	}
+	return "integer"
+}
+
+// floatToRat converts a Go floating point number of the given bit size into a rational.
+//
+// The number is converted through its shortest decimal representation,
+// so that a Go `0.1` is equal to the literal `0.1` and not to its exact binary value.
+func floatToRat(val float64, bitSize int) (*big.Rat, bool) {
+	if math.IsInf(val, 0) || math.IsNaN(val) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(strconv.FormatFloat(val, 'g', -1, bitSize))
+}
+
+// typeName returns the JSON type name of the given value for error messages.
+func typeName(data any) string {
+	if data == nil {
+		return "null"
+	}
+	switch val := data.(type) {
+	case json.Number:
+		r, ok := ToRat(val)
+		if ok && r.IsInt() {
+			return "integer"
+		}
+		return "number"
+	case int, int8, int16, int32, int64:
+		return "integer"
+	case uint, uint8, uint16, uint32, uint64:
+		return "unsigned integer"
+	case float32, float64:
+		return "number"
+	case *big.Int:
+		return "integer"
+	case *big.Rat, *big.Float:
+		return "number"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,242 @@
+package parser
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// property is a key-value pair of an [objectType].
+type property struct {
+	// The literal name of the property. Empty if rex is not nil.
+	name string
+	// If not nil, the property is validated for all keys matching the regex.
+	rex       *regexp.Regexp
+	validator valdo.Validator
+	// If true, the property can be missing. Regex properties are always optional.
+	optional bool
+}
+
+// newProperty creates a property for the key as written in the pattern.
+//
+// If the key starts with "^", it's interpreted as a regular expression.
+// The "^^" prefix escapes it: the key is literal and starts with a single "^".
+func newProperty(key string, v valdo.Validator) (property, error) {
+	if len(key) >= 2 && key[:2] == "^^" {
+		return property{name: key[1:], validator: v}, nil
+	}
+	if key != "" && key[0] == '^' {
+		rex, err := regexp.Compile(key)
+		if err != nil {
+			return property{}, err
+		}
+		return property{rex: rex, validator: v}, nil
+	}
+	return property{name: key, validator: v}, nil
+}
+
+// objectType is an object with the given properties.
+//
+// Unlike [valdo.ObjectType], it supports literal keys starting with "^"
+// and reports errors in regex properties with the actual key.
+type objectType struct {
+	props []property
+	// If true, properties not listed in the pattern are allowed.
+	extra bool
+}
+
+// Validate implements [valdo.Validator].
+//
+// Missing properties are compared to the properties not listed in the pattern,
+// so that a typo in a key is reported with a suggestion of the right key.
+// Such a key is reported only once, as missing, and not also as unexpected.
+func (obj objectType) Validate(data any) valdo.Error {
+	d, ok := data.(map[string]any)
+	if !ok || d == nil {
+		return valdo.ErrType{Got: typeName(data), Expected: "object"}
+	}
+	names := SortedKeys(d)
+	unhandled := obj.unhandled(names)
+	missing := make([]string, 0)
+	// Unexpected keys already suggested for missing properties.
+	suggested := make([]string, 0)
+	res := valdo.Errors{}
+	for _, p := range obj.props {
+		if p.rex != nil {
+			for _, name := range names {
+				if p.rex.MatchString(name) {
+					res.Add(validateProperty(name, p.validator, d[name]))
+				}
+			}
+			continue
+		}
+		val, found := d[p.name]
+		if !found && p.optional {
+			continue
+		}
+		if !found {
+			var err valdo.Error = valdo.ErrRequired{Name: p.name}
+			suggestion, similar := closest(p.name, unsuggested(unhandled, suggested))
+			if similar {
+				suggested = append(suggested, suggestion)
+				err = err.SetFormat("missing `{name}`; did you mean `" + suggestion + "`?")
+			} else {
+				missing = append(missing, p.name)
+			}
+			res.Add(locate(p.validator, err))
+			continue
+		}
+		res.Add(validateProperty(p.name, p.validator, val))
+	}
+	if !obj.extra {
+		for _, name := range unsuggested(unhandled, suggested) {
+			var err valdo.Error = valdo.ErrUnexpected{Name: name}
+			suggestion, similar := closest(name, missing)
+			if similar {
+				err = err.SetFormat("unexpected property `{name}`; did you mean `" + suggestion + "`?")
+			}
+			res.Add(err)
+		}
+	}
+	return res.Flatten()
+}
+
+// unhandled returns the given keys that don't match any property of the object.
+func (obj objectType) unhandled(names []string) []string {
+	res := make([]string, 0)
+	for _, name := range names {
+		matched := false
+		for _, p := range obj.props {
+			if p.rex != nil && p.rex.MatchString(name) || p.rex == nil && p.name == name {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// unsuggested returns the names except the ones already suggested.
+func unsuggested(names, suggested []string) []string {
+	if len(suggested) == 0 {
+		return names
+	}
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		if !slices.Contains(suggested, name) {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// Schema implements [valdo.Validator].
+func (obj objectType) Schema() jsony.Object {
+	required := make(jsony.Array[jsony.String], 0)
+	properties := make(jsony.UnsafeObject, 0, len(obj.props))
+	patternProps := make(jsony.UnsafeObject, 0)
+	for _, p := range obj.props {
+		if p.rex != nil {
+			f := jsony.UnsafeField{K: jsony.String(p.rex.String()), V: p.validator.Schema()}
+			patternProps = append(patternProps, f)
+			continue
+		}
+		if !p.optional {
+			required = append(required, jsony.String(p.name))
+		}
+		f := jsony.UnsafeField{K: jsony.String(p.name), V: p.validator.Schema()}
+		properties = append(properties, f)
+	}
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("object")},
+	}
+	if len(properties) > 0 {
+		res = append(res, jsony.Field{K: "properties", V: properties})
+	}
+	if len(patternProps) > 0 {
+		res = append(res, jsony.Field{K: "patternProperties", V: patternProps})
+	}
+	if len(required) > 0 {
+		res = append(res, jsony.Field{K: "required", V: required})
+	}
+	if !obj.extra {
+		res = append(res, jsony.Field{K: "additionalProperties", V: jsony.False})
+	}
+	return res
+}
+
+// mapType is an object with all keys and all values matching the given validators.
+type mapType struct {
+	key   valdo.Validator
+	value valdo.Validator
+	// The minimum number of properties, or -1 if not limited.
+	minProps int
+	// The maximum number of properties, or -1 if not limited.
+	maxProps int
+}
+
+// Validate implements [valdo.Validator].
+func (m mapType) Validate(data any) valdo.Error {
+	d, ok := data.(map[string]any)
+	if !ok || d == nil {
+		return valdo.ErrType{Got: typeName(data), Expected: "object"}
+	}
+	res := valdo.Errors{}
+	if m.minProps >= 0 && len(d) < m.minProps {
+		res.Add(valdo.ErrMinProperties{Value: m.minProps})
+	}
+	if m.maxProps >= 0 && len(d) > m.maxProps {
+		res.Add(valdo.ErrMaxProperties{Value: m.maxProps})
+	}
+	for _, name := range SortedKeys(d) {
+		err := m.key.Validate(name)
+		if err != nil {
+			res.Add(valdo.ErrPropertyNames{Name: name, Err: err})
+			continue
+		}
+		res.Add(validateProperty(name, m.value, d[name]))
+	}
+	return res.Flatten()
+}
+
+// Schema implements [valdo.Validator].
+func (m mapType) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("object")},
+		jsony.Field{K: "propertyNames", V: m.key.Schema()},
+		jsony.Field{K: "additionalProperties", V: m.value.Schema()},
+	}
+	if m.minProps >= 0 {
+		res = append(res, jsony.Field{K: "minProperties", V: jsony.Int(m.minProps)})
+	}
+	if m.maxProps >= 0 {
+		res = append(res, jsony.Field{K: "maxProperties", V: jsony.Int(m.maxProps)})
+	}
+	return res
+}
+
+// validateProperty validates the value of the property with the given name.
+func validateProperty(name string, v valdo.Validator, val any) valdo.Error {
+	err := v.Validate(val)
+	if err != nil {
+		return valdo.ErrProperty{Name: name, Err: err}
+	}
+	return nil
+}
+
+// SortedKeys returns the keys of the object in alphabetical order.
+//
+// Validators and error reports iterate over sorted keys so that errors are reported in a stable order.
+func SortedKeys(data map[string]any) []string {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -1,23 +1,67 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/orsinium-labs/testo/internal/lexer"
+	"github.com/orsinium-labs/valdo/regexes"
 	"github.com/orsinium-labs/valdo/valdo"
 )
 
-func Validate(given any, expected string) error {
-	validator, err := Parse(expected)
+// Config customizes how a pattern is parsed.
+type Config struct {
+	// The function returning the current time for `now` in time constraints.
+	//
+	// If nil, [time.Now] is used.
+	Clock func() time.Time
+
+	// Values of variables referenced in the pattern, like `$start`.
+	Vars map[string]any
+
+	// Sources of named patterns referenced in the pattern by name, like `user`.
+	Defs map[string]string
+
+	// If not nil, it's called with the value matched by every capture, like `$id = uuid`.
+	//
+	// The values are reported while the input is validated, so they can come
+	// from a part of the input that matched even if the whole input didn't.
+	// Trial matches of items of unordered arrays aren't reported.
+	// A validator with captures must not be used by multiple goroutines at once.
+	Capture func(name string, value any)
+
+	// If true, objects allow properties not listed in the pattern.
+	OpenObjects bool
+
+	// If true, items of arrays in the pattern, like `[1, 2]`, can be in any order.
+	UnorderedArrays bool
+
+	// If not nil, numbers in the pattern match values that differ by at most the tolerance.
+	Tolerance *big.Rat
+
+	// If true, numbers aren't converted between integers and floats:
+	// `int` doesn't match `13.0`, `float` doesn't match `13`, and `13` doesn't match `13.0`.
+	StrictTypes bool
+}
+
+func Validate(given any, expected string, config Config) error {
+	validator, err := Parse(expected, config)
 	if err != nil {
 		return err
 	}
-	return validator.Validate(given)
+	vErr := validator.Validate(given)
+	if vErr != nil {
+		return ValidationError{Err: vErr}
+	}
+	return nil
 }
 
-func Parse(input string) (valdo.Validator, error) {
-	return New(lexer.New(input)).Parse()
+func Parse(input string, config Config) (valdo.Validator, error) {
+	return New(lexer.New(input), config).Parse()
 }
 
 // Parser is responsible for parsing tokens into a structured format.
@@ -25,11 +69,32 @@ type Parser struct {
 	l         *lexer.Lexer
 	curToken  lexer.Token
 	peekToken lexer.Token
+	// The last consumed token, used to find where a value ends in the pattern.
+	prevToken lexer.Token
+	config    Config
+
+	// If true, objects allow properties not listed in the pattern.
+	openObjects bool
+
+	// Delivers the captured values to [Config.Capture]. Nil if it isn't set.
+	captures *captures
+
+	// Syntax errors found so far.
+	errors []*SyntaxError
+
+	// Names of the named patterns being parsed, used to detect recursive definitions.
+	defining []string
 }
 
 // New creates a new Parser instance.
-func New(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l}
+func New(l *lexer.Lexer, config Config) *Parser {
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	p := &Parser{l: l, config: config, openObjects: config.OpenObjects}
+	if config.Capture != nil {
+		p.captures = &captures{sink: config.Capture}
+	}
 	// Initialize curToken and peekToken
 	p.nextToken()
 	p.nextToken()
@@ -38,71 +103,248 @@ func New(l *lexer.Lexer) *Parser {
 
 // nextToken advances the parser to the next token.
 func (p *Parser) nextToken() {
+	p.prevToken = p.curToken
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
 }
 
 // Parse parses the input starting from the root and returns the root valdo.Validator.
+//
+// If the pattern has syntax errors, all of them are reported.
+// See [Parser.recover] for how the parser continues after an error.
 func (p *Parser) Parse() (valdo.Validator, error) {
 	validator, err := p.parseValue()
 	if err != nil {
-		return nil, err
+		p.report(err)
+	} else if p.curToken.Type != lexer.EOF {
+		p.report(p.errorf(p.curToken, "expected end of input after the pattern, got %s", describe(p.curToken)))
 	}
-	p.nextToken()
-	if p.curToken.Type != lexer.EOF {
-		return nil, fmt.Errorf("expected EOF, found, %s", p.curToken.Type)
+	if len(p.errors) == 1 {
+		return nil, p.errors[0]
+	}
+	if len(p.errors) > 1 {
+		return nil, SyntaxErrors(p.errors)
 	}
 	return validator, nil
 }
 
 // parseObject parses an object and returns an ObjectValue node.
+//
+// Errors in properties are reported and the parser skips to the next property.
 func (p *Parser) parseObject() (valdo.Validator, error) {
-	props := make([]valdo.PropertyType, 0)
+	props := make([]property, 0)
 
 	p.nextToken()
 
 	// Handle an empty object
 	if p.curToken.Type == lexer.RBRACE {
 		p.nextToken()
-		return valdo.O(), nil
+		return objectType{props: props, extra: p.openObjects}, nil
 	}
 
 	// Parse object contents.
-	for p.curToken.Type != lexer.EOF {
-		key, err := p.parseKey()
+	for {
+		prop, key, err := p.parseProperty()
 		if err != nil {
-			return nil, err
+			p.recover(err)
+		} else {
+			props = append(props, prop)
+			if p.curToken.Type != lexer.COMMA && p.curToken.Type != lexer.RBRACE {
+				p.recover(p.errorf(p.curToken, "expected ',' or '}' after the value of %q, got %s", key, describe(p.curToken)))
+			}
 		}
 
-		if p.curToken.Type != lexer.COLON {
-			return nil, fmt.Errorf("expected ':', got %s at line %d, column %d", p.curToken.Type, p.curToken.Line, p.curToken.Column)
+		switch p.curToken.Type {
+		case lexer.COMMA:
+			p.nextToken()
+		case lexer.RBRACE:
+			p.nextToken()
+			return objectType{props: props, extra: p.openObjects}, nil
+		default:
+			// The end of input or a mismatched bracket. The error is already reported.
+			return objectType{props: props, extra: p.openObjects}, nil
 		}
+	}
+}
+
+// parseProperty parses a key-value pair in an object.
+func (p *Parser) parseProperty() (property, string, error) {
+	keyToken := p.curToken
+	key, err := p.parseKey()
+	if err != nil {
+		return property{}, "", err
+	}
+
+	// The property is optional, like `"nickname"?: string`.
+	optional := p.curToken.Type == lexer.QUESTION
+	if optional {
 		p.nextToken()
+	}
 
-		value, err := p.parseValue()
+	if p.curToken.Type != lexer.COLON {
+		return property{}, key, p.errorf(p.curToken, "expected ':' after key %q, got %s", key, describe(p.curToken))
+	}
+	p.nextToken()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return property{}, key, err
+	}
+
+	prop, err := newProperty(key, value)
+	if err != nil {
+		return property{}, key, p.errorf(keyToken, "invalid regex key: %v", err)
+	}
+	prop.optional = optional
+	return prop, key, nil
+}
+
+// report records the syntax error.
+//
+// An error at the same position as the previous one is a consequence of it,
+// so it's not reported.
+func (p *Parser) report(err error) {
+	var sErr *SyntaxError
+	if !errors.As(err, &sErr) {
+		sErr = &SyntaxError{Line: p.curToken.Line, Column: p.curToken.Column, Offset: p.curToken.Offset, Msg: err.Error()}
+	}
+	if len(p.errors) > 0 && p.errors[len(p.errors)-1].Offset == sErr.Offset {
+		return
+	}
+	p.errors = append(p.errors, sErr)
+}
+
+// recover reports the error and skips tokens until the end of the current item
+// of an object or array, so that the parser can continue and find more errors.
+//
+// It stops at ',', '}', or ']' outside of nested brackets or at the end of input.
+func (p *Parser) recover(err error) {
+	p.report(err)
+	depth := 0
+	for {
+		switch p.curToken.Type {
+		case lexer.EOF:
+			return
+		case lexer.COMMA:
+			if depth == 0 {
+				return
+			}
+		case lexer.LBRACE, lexer.LBRACKET, lexer.LPAREN:
+			depth++
+		case lexer.RBRACE, lexer.RBRACKET:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case lexer.RPAREN:
+			// Parentheses don't close objects and arrays, so a stray one is skipped.
+			if depth > 0 {
+				depth--
+			}
+		}
+		p.nextToken()
+	}
+}
+
+// parseMap parses an object with typed keys, like `map[uuid]int` or `map[/^[a-z]+$/, 1..10]any`.
+//
+// The current token is the opening bracket after the keyword.
+func (p *Parser) parseMap() (valdo.Validator, error) {
+	p.nextToken()
+	key, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	result := mapType{key: key, minProps: -1, maxProps: -1}
+
+	// Parse the optional number of properties.
+	if p.curToken.Type == lexer.COMMA {
+		p.nextToken()
+		rangeToken := p.curToken
+		min, max, err := p.parseRange()
 		if err != nil {
 			return nil, err
 		}
-
-		props = append(props, valdo.P(key, value))
-		if p.curToken.Type == lexer.RBRACE {
-			p.nextToken()
-			return valdo.O(props...), nil
+		result.minProps, err = ratToCount(min)
+		if err == nil {
+			result.maxProps, err = ratToCount(max)
+		}
+		if err != nil {
+			return nil, p.errorf(rangeToken, "invalid number of properties: %v", err)
 		}
+	}
 
-		if p.curToken.Type != lexer.COMMA {
-			return nil, fmt.Errorf("expected ',' or '}', got %s at line %d, column %d", p.curToken.Type, p.curToken.Line, p.curToken.Column)
+	if p.curToken.Type != lexer.RBRACKET {
+		return nil, p.errorf(p.curToken, "expected ']', got %s", describe(p.curToken))
+	}
+	p.nextToken()
+	result.value, err = p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseRange parses an inclusive range of numbers, like `1..10`, `1..`, `..10`, or just `10`.
+//
+// A nil bound means that the range is unbounded from that side.
+func (p *Parser) parseRange() (*big.Rat, *big.Rat, error) {
+	var min, max *big.Rat
+	var err error
+	if p.curToken.Type == lexer.NUMBER {
+		min, err = p.parseNumber()
+		if err != nil {
+			return nil, nil, err
+		}
+		if p.curToken.Type != lexer.DOTDOT {
+			return min, min, nil
+		}
+	}
+	if p.curToken.Type != lexer.DOTDOT {
+		return nil, nil, p.errorf(p.curToken, "expected range, got %s", describe(p.curToken))
+	}
+	p.nextToken()
+	if p.curToken.Type == lexer.NUMBER {
+		max, err = p.parseNumber()
+		if err != nil {
+			return nil, nil, err
 		}
-		p.nextToken()
 	}
+	if min == nil && max == nil {
+		return nil, nil, p.errorf(p.curToken, "expected at least one bound of the range")
+	}
+	if min != nil && max != nil && min.Cmp(max) > 0 {
+		return nil, nil, p.errorf(p.curToken, "the lower bound of the range is greater than the upper bound")
+	}
+	return min, max, nil
+}
+
+// parseNumber parses a number literal.
+func (p *Parser) parseNumber() (*big.Rat, error) {
+	// Keep the exact value of the literal, without rounding it through float64.
+	value, ok := new(big.Rat).SetString(p.curToken.Literal)
+	if !ok {
+		return nil, p.errorf(p.curToken, "could not parse number %s", p.curToken.Literal)
+	}
+	p.nextToken()
+	return value, nil
+}
 
-	return nil, fmt.Errorf("unexpected end of input")
+// ratToCount converts a range bound into a number of items, or -1 if there is no bound.
+func ratToCount(value *big.Rat) (int, error) {
+	if value == nil {
+		return -1, nil
+	}
+	if !value.IsInt() || value.Sign() < 0 || !value.Num().IsInt64() {
+		return 0, fmt.Errorf("%s is not a non-negative integer", value.RatString())
+	}
+	return int(value.Num().Int64()), nil
 }
 
 // parseKey parses a key in an object.
 func (p *Parser) parseKey() (string, error) {
 	if p.curToken.Type != lexer.STRING {
-		return "", fmt.Errorf("expected string key, got %s at line %d, column %d", p.curToken.Type, p.curToken.Line, p.curToken.Column)
+		return "", p.errorf(p.curToken, "expected string key, got %s", describe(p.curToken))
 	}
 	key := p.curToken.Literal
 	p.nextToken()
@@ -110,22 +352,53 @@ func (p *Parser) parseKey() (string, error) {
 }
 
 // parseValue parses a value in an object or array and returns a Value node.
+//
+// The returned validator remembers where the value is in the pattern,
+// so that validation errors can point to it.
 func (p *Parser) parseValue() (valdo.Validator, error) {
+	start := p.curToken
+	value, err := p.parseBareValue()
+	if err != nil {
+		return nil, err
+	}
+	return located{
+		inner:    value,
+		line:     start.Line,
+		column:   start.Column,
+		fragment: p.l.Input()[start.Offset:p.prevToken.End],
+	}, nil
+}
+
+// parseBareValue parses a value without remembering its position.
+func (p *Parser) parseBareValue() (valdo.Validator, error) {
 	switch p.curToken.Type {
 	case lexer.STRING:
 		value := valdo.Const(p.curToken.Literal)
 		p.nextToken()
 		return value, nil
 	case lexer.NUMBER:
-		// Convert the string literal to a float64
-		numValue, err := strconv.ParseFloat(p.curToken.Literal, 64)
+		literal := p.curToken.Literal
+		numValue, err := p.parseNumber()
 		if err != nil {
-			return nil, fmt.Errorf("could not parse number: %v", err)
+			return nil, err
+		}
+		return numConst{value: numValue, literal: literal, tolerance: p.config.Tolerance, strict: p.config.StrictTypes}, nil
+	case lexer.STRING_MODE:
+		mode := p.curToken.Literal
+		p.nextToken()
+		if p.curToken.Type != lexer.STRING {
+			return nil, p.errorf(p.curToken, "expected string after %s, got %s", mode, describe(p.curToken))
 		}
-		// TODO: support float.
-		value := valdo.IntConst(int(numValue))
+		value := newStringMatch(mode, p.curToken.Literal)
 		p.nextToken()
 		return value, nil
+	case lexer.REGEX:
+		rex, err := regexp.Compile(p.curToken.Literal)
+		if err != nil {
+			return nil, p.errorf(p.curToken, "invalid regex: %v", err)
+		}
+		p.nextToken()
+		return regexType{rex: rex}, nil
 	case lexer.TRUE:
 		value := valdo.BoolConst(true)
 		p.nextToken()
@@ -147,59 +420,363 @@ func (p *Parser) parseValue() (valdo.Validator, error) {
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_STRING:
-		value := valdo.String()
 		p.nextToken()
-		return value, nil
-	case lexer.TYPE_INT:
-		value := valdo.Int()
+		if p.curToken.Type == lexer.LPAREN {
+			return p.parseScalarString()
+		}
+		return valdo.String(), nil
+	case lexer.TYPE_NUMSTR:
 		p.nextToken()
-		return value, nil
-	case lexer.TYPE_UINT:
-		value := valdo.Int(valdo.Min(0))
+		return scalarString{inner: floatType{}}, nil
+	case lexer.TYPE_FLOATSTR:
 		p.nextToken()
-		return value, nil
-	case lexer.TYPE_FLOAT:
-		value := valdo.Float64()
+		return scalarString{inner: floatType{strict: p.config.StrictTypes}}, nil
+	case lexer.TYPE_INTSTR:
 		p.nextToken()
-		return value, nil
+		return scalarString{inner: intType{strict: p.config.StrictTypes}}, nil
+	case lexer.TYPE_BOOLSTR:
+		p.nextToken()
+		return scalarString{inner: valdo.Bool()}, nil
+	case lexer.TYPE_INT:
+		return p.parseInt(intType{})
+	case lexer.TYPE_UINT:
+		return p.parseInt(intType{min: zero})
+	case lexer.TYPE_I8:
+		return p.parseInt(intType{min: minI8, max: maxI8})
+	case lexer.TYPE_I16:
+		return p.parseInt(intType{min: minI16, max: maxI16})
+	case lexer.TYPE_I32:
+		return p.parseInt(intType{min: minI32, max: maxI32})
+	case lexer.TYPE_I64:
+		return p.parseInt(intType{min: minI64, max: maxI64})
+	case lexer.TYPE_U8:
+		return p.parseInt(intType{min: zero, max: maxU8})
+	case lexer.TYPE_U16:
+		return p.parseInt(intType{min: zero, max: maxU16})
+	case lexer.TYPE_U32:
+		return p.parseInt(intType{min: zero, max: maxU32})
+	case lexer.TYPE_U64:
+		return p.parseInt(intType{min: zero, max: maxU64})
+	case lexer.TYPE_SAFEINT:
+		return p.parseInt(intType{min: minSafeInt, max: maxSafeInt})
+	case lexer.TYPE_FLOAT:
+		return p.parseFloat()
 	case lexer.TYPE_BOOL:
 		value := valdo.Bool()
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_OBJECT:
-		value := valdo.Map(valdo.Any())
 		p.nextToken()
-		return value, nil
+		if p.curToken.Type == lexer.LBRACKET {
+			return p.parseMap()
+		}
+		return valdo.Map(valdo.Any()), nil
 	case lexer.TYPE_ARRAY:
-		value := valdo.Array(valdo.Any())
+		value := arrayType{item: valdo.Any()}
+		p.nextToken()
+		return value, nil
+	case lexer.TYPE_UUID:
+		value := valdo.String(valdo.Pattern(regexes.UUIDRFC4122))
 		p.nextToken()
 		return value, nil
+	case lexer.TYPE_DATETIME, lexer.TYPE_DATE:
+		return p.parseTime()
+	case lexer.TYPE_JSON:
+		args, err := p.parseArguments(0, 1)
+		if err != nil {
+			return nil, err
+		}
+		return jsonType{inner: optionalArg(args, 0)}, nil
+	case lexer.TYPE_BASE64:
+		args, err := p.parseArguments(0, 1)
+		if err != nil {
+			return nil, err
+		}
+		return base64Type{inner: optionalArg(args, 0)}, nil
+	case lexer.TYPE_JWT:
+		// Decoded JWTs usually have a lot of registered claims (like "iat" or "iss"),
+		// so objects in the pattern are open to allow listing only interesting claims.
+		open := p.openObjects
+		p.openObjects = true
+		args, err := p.parseArguments(0, 2)
+		p.openObjects = open
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 2 {
+			return jwtType{header: args[0], claims: args[1]}, nil
+		}
+		return jwtType{claims: optionalArg(args, 0)}, nil
+	case lexer.TYPE_URL:
+		// Empty URL components are omitted and most query parameters are usually irrelevant,
+		// so objects in the pattern are open.
+		open := p.openObjects
+		p.openObjects = true
+		args, err := p.parseArguments(0, 1)
+		p.openObjects = open
+		if err != nil {
+			return nil, err
+		}
+		return urlType{inner: optionalArg(args, 0)}, nil
 	case lexer.TYPE_STRINGS:
-		value := valdo.Array(valdo.String())
+		value := arrayType{item: valdo.String()}
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_INTS:
-		value := valdo.Array(valdo.Int())
+		value := arrayType{item: intType{strict: p.config.StrictTypes}}
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_UINTS:
-		value := valdo.Array(valdo.Int(valdo.Min(0)))
+		value := arrayType{item: intType{min: zero, strict: p.config.StrictTypes}}
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_FLOATS:
-		value := valdo.Array(valdo.Float64())
+		value := arrayType{item: floatType{strict: p.config.StrictTypes}}
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_BOOLS:
-		value := valdo.Array(valdo.Bool())
+		value := arrayType{item: valdo.Bool()}
 		p.nextToken()
 		return value, nil
 	case lexer.TYPE_OBJECTS:
-		value := valdo.Array(valdo.Map(valdo.Any()))
+		value := arrayType{item: valdo.Map(valdo.Any())}
 		p.nextToken()
 		return value, nil
+	case lexer.VARIABLE:
+		return p.parseVariable()
+	case lexer.IDENT:
+		return p.parseReference()
 	default:
-		return nil, fmt.Errorf("unexpected token %s at line %d, column %d", p.curToken.Type, p.curToken.Line, p.curToken.Column)
+		return nil, p.errorf(p.curToken, "expected a value, got %s", describe(p.curToken))
+	}
+}
+
+// parseArguments parses the keyword and optional patterns in parentheses following it, like `json(...)`.
+//
+// If there are no parentheses, no arguments are returned.
+func (p *Parser) parseArguments(min, max int) ([]valdo.Validator, error) {
+	keyword := p.curToken
+	p.nextToken()
+	args := make([]valdo.Validator, 0)
+	if p.curToken.Type == lexer.LPAREN {
+		p.nextToken()
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, value)
+
+			if p.curToken.Type == lexer.RPAREN {
+				p.nextToken()
+				break
+			}
+			if p.curToken.Type != lexer.COMMA {
+				return nil, p.errorf(p.curToken, "expected ',' or ')', got %s", describe(p.curToken))
+			}
+			p.nextToken()
+		}
+	}
+	if len(args) < min || len(args) > max {
+		return nil, p.errorf(
+			keyword, "%s accepts from %d to %d arguments, got %d",
+			keyword.Literal, min, max, len(args),
+		)
+	}
+	return args, nil
+}
+
+// optionalArg returns the argument at the given index or nil if there is no such argument.
+func optionalArg(args []valdo.Validator, index int) valdo.Validator {
+	if index < len(args) {
+		return args[index]
+	}
+	return nil
+}
+
+// parseInt parses an integer keyword with an optional range, like `int(1..10)`.
+//
+// The range must be within the given bounds of the keyword.
+func (p *Parser) parseInt(bounds intType) (valdo.Validator, error) {
+	keyword := p.curToken
+	p.nextToken()
+	min, max, err := p.parseRangeArgument()
+	if err != nil {
+		return nil, err
+	}
+	result := bounds
+	if min != nil {
+		if !min.IsInt() {
+			return nil, p.errorf(keyword, "%s range bound must be an integer", keyword.Literal)
+		}
+		result.min = min.Num()
+	}
+	if max != nil {
+		if !max.IsInt() {
+			return nil, p.errorf(keyword, "%s range bound must be an integer", keyword.Literal)
+		}
+		result.max = max.Num()
+	}
+	outOfBounds := (bounds.min != nil && (result.min == nil || result.min.Cmp(bounds.min) < 0)) ||
+		(bounds.max != nil && (result.max == nil || result.max.Cmp(bounds.max) > 0))
+	if outOfBounds {
+		return nil, p.errorf(keyword, "range is out of bounds of %s", keyword.Literal)
+	}
+	result.strict = p.config.StrictTypes
+	return result, nil
+}
+
+// parseFloat parses a number keyword with an optional range, like `float(0..1)`.
+func (p *Parser) parseFloat() (valdo.Validator, error) {
+	p.nextToken()
+	min, max, err := p.parseRangeArgument()
+	if err != nil {
+		return nil, err
+	}
+	return floatType{min: min, max: max, strict: p.config.StrictTypes}, nil
+}
+
+// parseRangeArgument parses an optional range in parentheses, like `(1..10)`.
+//
+// If there are no parentheses, both bounds are nil.
+func (p *Parser) parseRangeArgument() (*big.Rat, *big.Rat, error) {
+	if p.curToken.Type != lexer.LPAREN {
+		return nil, nil, nil
+	}
+	p.nextToken()
+	min, max, err := p.parseRange()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, nil, p.errorf(p.curToken, "expected ')', got %s", describe(p.curToken))
+	}
+	p.nextToken()
+	return min, max, nil
+}
+
+// parseScalarString parses a string containing a JSON scalar, like `str(int(1..10))`.
+//
+// The current token is the opening parenthesis after the keyword.
+func (p *Parser) parseScalarString() (valdo.Validator, error) {
+	p.nextToken()
+	inner, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if p.curToken.Type != lexer.RPAREN {
+		return nil, p.errorf(p.curToken, "expected ')', got %s", describe(p.curToken))
+	}
+	p.nextToken()
+	return scalarString{inner: inner}, nil
+}
+
+// parseTime parses a timestamp or a date with optional constraints, like `datetime(within 5s of now)`.
+func (p *Parser) parseTime() (valdo.Validator, error) {
+	result := timeType{
+		date:  p.curToken.Type == lexer.TYPE_DATE,
+		clock: p.config.Clock,
+	}
+	p.nextToken()
+	if p.curToken.Type != lexer.LPAREN {
+		return result, nil
+	}
+	p.nextToken()
+	for {
+		c, err := p.parseTimeConstraint()
+		if err != nil {
+			return nil, err
+		}
+		result.cs = append(result.cs, c)
+
+		if p.curToken.Type == lexer.RPAREN {
+			p.nextToken()
+			return result, nil
+		}
+		if p.curToken.Type != lexer.COMMA {
+			return nil, p.errorf(p.curToken, "expected ',' or ')', got %s", describe(p.curToken))
+		}
+		p.nextToken()
+	}
+}
+
+// parseTimeConstraint parses a single time constraint.
+//
+// Supported constraints are `within <duration> of <ref>`, `after <ref>`, `before <ref>`,
+// and comparisons: `> <ref>`, `>= <ref>`, `< <ref>`, `<= <ref>`.
+func (p *Parser) parseTimeConstraint() (timeConstraint, error) {
+	var c timeConstraint
+	tok := p.curToken
+	switch {
+	case tok.Type == lexer.GT, tok.Type == lexer.GTE, tok.Type == lexer.LT, tok.Type == lexer.LTE:
+		c.op = tok.Literal
+		c.text = tok.Literal
+	case tok.Type == lexer.IDENT && tok.Literal == "after":
+		c.op = ">"
+		c.text = "after"
+	case tok.Type == lexer.IDENT && tok.Literal == "before":
+		c.op = "<"
+		c.text = "before"
+	case tok.Type == lexer.IDENT && tok.Literal == "within":
+		p.nextToken()
+		if p.curToken.Type != lexer.DURATION {
+			return c, p.errorf(p.curToken, "expected duration, got %s", describe(p.curToken))
+		}
+		delta, err := time.ParseDuration(p.curToken.Literal)
+		if err != nil || delta < 0 {
+			return c, p.errorf(p.curToken, "invalid duration %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		if p.curToken.Type != lexer.IDENT || p.curToken.Literal != "of" {
+			return c, p.errorf(p.curToken, "expected 'of', got %s", describe(p.curToken))
+		}
+		c.op = "within"
+		c.delta = delta
+		c.text = "within " + delta.String() + " of"
+	default:
+		return c, p.errorf(tok, "expected time constraint, got %s", describe(tok))
+	}
+	p.nextToken()
+
+	ref, refText, err := p.parseTimeRef()
+	if err != nil {
+		return c, err
+	}
+	c.ref = ref
+	c.text += " " + refText
+	return c, nil
+}
+
+// parseTimeRef parses a point in time: `now`, a variable, or a string literal.
+func (p *Parser) parseTimeRef() (timeRef, string, error) {
+	tok := p.curToken
+	switch tok.Type {
+	case lexer.IDENT:
+		if tok.Literal != "now" {
+			return timeRef{}, "", p.errorf(tok, "unknown time reference %s", tok.Literal)
+		}
+		p.nextToken()
+		return timeRef{now: true}, "now", nil
+	case lexer.VARIABLE:
+		raw, found := p.config.Vars[tok.Literal]
+		if !found {
+			return timeRef{}, "", p.errorf(tok, "undefined variable $%s", tok.Literal)
+		}
+		value, err := toTime(raw)
+		if err != nil {
+			return timeRef{}, "", p.errorf(tok, "invalid time in variable $%s: %v", tok.Literal, err)
+		}
+		p.nextToken()
+		return timeRef{value: value}, "$" + tok.Literal, nil
+	case lexer.STRING:
+		value, err := toTime(tok.Literal)
+		if err != nil {
+			return timeRef{}, "", p.errorf(tok, "invalid time %q: %v", tok.Literal, err)
+		}
+		p.nextToken()
+		return timeRef{value: value}, strconv.Quote(tok.Literal), nil
+	default:
+		return timeRef{}, "", p.errorf(tok, "expected time, got %s", describe(tok))
 	}
 }
 
@@ -211,24 +788,47 @@ func (p *Parser) parseArray() (valdo.Validator, error) {
 	// Handle an empty array.
 	if p.curToken.Type == lexer.RBRACKET {
 		p.nextToken()
-		return valdo.T(), nil
+		return tupleType{}, nil
 	}
 
 	for {
 		value, err := p.parseValue()
 		if err != nil {
-			return nil, err
+			p.recover(err)
+		} else if p.curToken.Type == lexer.ELLIPSIS {
+			return p.parseRepeated(value, len(items))
+		} else {
+			items = append(items, value)
+			if p.curToken.Type != lexer.COMMA && p.curToken.Type != lexer.RBRACKET {
+				p.recover(p.errorf(p.curToken, "expected ',' or ']' after the array item, got %s", describe(p.curToken)))
+			}
 		}
-		items = append(items, value)
 
-		if p.curToken.Type == lexer.RBRACKET {
+		switch p.curToken.Type {
+		case lexer.COMMA:
+			p.nextToken()
+		case lexer.RBRACKET:
 			p.nextToken()
-			return valdo.T(items...), nil
+			return tupleType{items: items, unordered: p.config.UnorderedArrays, captures: p.captures}, nil
+		default:
+			// The end of input or a mismatched bracket. The error is already reported.
+			return tupleType{items: items, unordered: p.config.UnorderedArrays, captures: p.captures}, nil
 		}
+	}
+}
 
-		if p.curToken.Type != lexer.COMMA {
-			return nil, fmt.Errorf("expected ',' or ']', got %s at line %d, column %d", p.curToken.Type, p.curToken.Line, p.curToken.Column)
-		}
-		p.nextToken()
+// parseRepeated parses the end of a homogeneous array, like `[int...]`, after the item pattern.
+//
+// The index is the position of the item in the array, which must be the only one.
+func (p *Parser) parseRepeated(item valdo.Validator, index int) (valdo.Validator, error) {
+	ellipsis := p.curToken
+	if index != 0 {
+		return nil, p.errorf(ellipsis, "'...' can follow only the single item of the array")
 	}
+	p.nextToken()
+	if p.curToken.Type != lexer.RBRACKET {
+		return nil, p.errorf(p.curToken, "expected ']' after '...', got %s", describe(p.curToken))
+	}
+	p.nextToken()
+	return arrayType{item: item}, nil
 }
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// Positions maps JSON Pointers of values in a JSON document to their positions in the text.
+type Positions struct {
+	offsets map[string]int
+	// Byte offsets of the first character of every line.
+	lines []int
+}
+
+// ScanPositions finds the position of every value in the JSON document.
+//
+// The document must be valid JSON, as checked by [DecodeJSON].
+func ScanPositions(raw []byte) Positions {
+	s := posScanner{raw: raw, offsets: make(map[string]int)}
+	s.value("")
+	lines := []int{0}
+	for i, ch := range raw {
+		if ch == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return Positions{offsets: s.offsets, lines: lines}
+}
+
+// Find returns the line and column of the value at the JSON Pointer.
+//
+// If the value isn't in the document, like a missing property,
+// the position of the closest parent is returned. If nothing is found, ok is false.
+// For a value decoded from a string, use the pointer to the string, see [Undecoded].
+func (ps Positions) Find(path string) (line, column int, ok bool) {
+	if ps.offsets == nil {
+		return 0, 0, false
+	}
+	for {
+		offset, found := ps.offsets[path]
+		if found {
+			line = sort.Search(len(ps.lines), func(i int) bool { return ps.lines[i] > offset })
+			return line, offset - ps.lines[line-1] + 1, true
+		}
+		if path == "" {
+			return 0, 0, false
+		}
+		path = parentPointer(path)
+	}
+}
+
+// parentPointer returns the JSON Pointer to the parent of the value.
+func parentPointer(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[:i]
+		}
+	}
+	return ""
+}
+
+// posScanner records positions of values while walking over a JSON document.
+type posScanner struct {
+	raw     []byte
+	pos     int
+	offsets map[string]int
+}
+
+// value scans the value at the current position that has the given JSON Pointer.
+func (s *posScanner) value(path string) {
+	s.skipSpace()
+	if s.pos >= len(s.raw) {
+		return
+	}
+	s.offsets[path] = s.pos
+	switch s.raw[s.pos] {
+	case '{':
+		s.pos++
+		for {
+			s.skipSpace()
+			if s.pos >= len(s.raw) {
+				return
+			}
+			switch s.raw[s.pos] {
+			case '}':
+				s.pos++
+				return
+			case ',':
+				s.pos++
+			case '"':
+				key := s.str()
+				s.skipSpace()
+				if s.pos < len(s.raw) && s.raw[s.pos] == ':' {
+					s.pos++
+				}
+				s.value(path + "/" + escapePointer(key))
+			default:
+				return
+			}
+		}
+	case '[':
+		s.pos++
+		for index := 0; ; index++ {
+			s.skipSpace()
+			if s.pos >= len(s.raw) {
+				return
+			}
+			switch s.raw[s.pos] {
+			case ']':
+				s.pos++
+				return
+			case ',':
+				s.pos++
+			}
+			start := s.pos
+			s.value(path + "/" + strconv.Itoa(index))
+			if s.pos == start {
+				return
+			}
+		}
+	case '"':
+		s.str()
+	default:
+		for s.pos < len(s.raw) && !isDelimiter(s.raw[s.pos]) {
+			s.pos++
+		}
+	}
+}
+
+// str scans a string literal and returns its decoded value.
+func (s *posScanner) str() string {
+	start := s.pos
+	s.pos++
+	for s.pos < len(s.raw) {
+		ch := s.raw[s.pos]
+		s.pos++
+		if ch == '\\' {
+			s.pos++
+		} else if ch == '"' {
+			break
+		}
+	}
+	var res string
+	err := json.Unmarshal(s.raw[start:min(s.pos, len(s.raw))], &res)
+	if err != nil {
+		return ""
+	}
+	return res
+}
+
+// skipSpace skips over whitespace.
+func (s *posScanner) skipSpace() {
+	for s.pos < len(s.raw) && isSpace(s.raw[s.pos]) {
+		s.pos++
+	}
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func isDelimiter(ch byte) bool {
+	return isSpace(ch) || ch == ',' || ch == ']' || ch == '}' || ch == ':'
+}
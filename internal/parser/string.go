@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/valdo"
+	"golang.org/x/text/unicode/norm"
+)
+
+// regexType is a string matching the given regular expression.
+type regexType struct {
+	rex *regexp.Regexp
+}
+
+// Validate implements [valdo.Validator].
+func (v regexType) Validate(data any) valdo.Error {
+	got, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	if !v.rex.MatchString(got) {
+		return valdo.ErrPattern{}
+	}
+	return nil
+}
+
+// Schema implements [valdo.Validator].
+func (v regexType) Schema() jsony.Object {
+	return jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+		jsony.Field{K: "pattern", V: jsony.String(v.rex.String())},
+	}
+}
+
+// stringMatch is a string matching the expected literal in the given mode, like `i"hello"`.
+type stringMatch struct {
+	// One of "i", "glob", "trim", or "nfc".
+	mode     string
+	expected string
+	// The compiled pattern for the "glob" mode.
+	rex *regexp.Regexp
+}
+
+// newStringMatch creates a string matcher for the literal with the given mode prefix.
+func newStringMatch(mode, expected string) stringMatch {
+	m := stringMatch{mode: mode, expected: expected}
+	switch mode {
+	case "glob":
+		m.rex = globToRegex(expected)
+	case "trim":
+		m.expected = strings.TrimSpace(expected)
+	case "nfc":
+		m.expected = norm.NFC.String(expected)
+	}
+	return m
+}
+
+// Validate implements [valdo.Validator].
+func (m stringMatch) Validate(data any) valdo.Error {
+	got, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	var matched bool
+	switch m.mode {
+	case "i":
+		matched = strings.EqualFold(got, m.expected)
+	case "glob":
+		matched = m.rex.MatchString(got)
+	case "trim":
+		matched = strings.TrimSpace(got) == m.expected
+	case "nfc":
+		matched = norm.NFC.String(got) == m.expected
+	}
+	if matched {
+		return nil
+	}
+	return valdo.ErrConst{
+		Format:   "expected the value to match {expected}",
+		Got:      got,
+		Expected: m.mode + strconv.Quote(m.expected),
+	}
+}
+
+// Schema implements [valdo.Validator].
+func (m stringMatch) Schema() jsony.Object {
+	res := jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+	}
+	if m.rex != nil {
+		res = append(res, jsony.Field{K: "pattern", V: jsony.String(m.rex.String())})
+	}
+	return res
+}
+
+// globToRegex converts a glob pattern into an anchored regular expression.
+//
+// In the glob, `*` matches any sequence of characters, `?` matches
+// any single character, and a backslash escapes the next character.
+func globToRegex(glob string) *regexp.Regexp {
+	var rex strings.Builder
+	rex.WriteString(`(?s)^`)
+	escaped := false
+	for _, ch := range glob {
+		switch {
+		case escaped:
+			rex.WriteString(regexp.QuoteMeta(string(ch)))
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case ch == '*':
+			rex.WriteString(`.*`)
+		case ch == '?':
+			rex.WriteString(`.`)
+		default:
+			rex.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	if escaped {
+		rex.WriteString(`\\`)
+	}
+	rex.WriteString(`$`)
+	return regexp.MustCompile(rex.String())
+}
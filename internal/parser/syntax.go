@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/orsinium-labs/testo/internal/lexer"
+)
+
+// SyntaxError is an error in the pattern syntax.
+type SyntaxError struct {
+	// The position of the first character of the offending token.
+	Line   int
+	Column int
+	Offset int
+	// The description of the error, like "expected ':' after key \"name\", got keyword int".
+	Msg string
+}
+
+// Error implements [error] interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Msg, e.Line, e.Column)
+}
+
+// errorf creates a [SyntaxError] pointing to the given token.
+func (p *Parser) errorf(tok lexer.Token, format string, args ...any) error {
+	return &SyntaxError{
+		Line:   tok.Line,
+		Column: tok.Column,
+		Offset: tok.Offset,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// unknownIdentifier creates an error for an identifier that isn't a keyword or a named pattern,
+// suggesting the closest one, if any.
+func (p *Parser) unknownIdentifier(tok lexer.Token) error {
+	candidates := append(lexer.Keywords(), slices.Sorted(maps.Keys(p.config.Defs))...)
+	suggestion, found := closest(tok.Literal, candidates)
+	if !found {
+		return p.errorf(tok, "unknown keyword %q", tok.Literal)
+	}
+	return p.errorf(tok, "unknown keyword %q; did you mean %q?", tok.Literal, suggestion)
+}
+
+// describe returns a human-readable description of the token for error messages.
+func describe(tok lexer.Token) string {
+	switch tok.Type {
+	case lexer.EOF:
+		return "end of input"
+	case lexer.ILLEGAL:
+		if len(tok.Literal) == 1 {
+			return "unexpected character " + strconv.QuoteRune(rune(tok.Literal[0]))
+		}
+		return strings.ToLower(tok.Literal)
+	case lexer.STRING:
+		return "string " + strconv.Quote(tok.Literal)
+	case lexer.NUMBER:
+		return "number " + tok.Literal
+	case lexer.REGEX:
+		return "regex /" + tok.Literal + "/"
+	case lexer.STRING_MODE:
+		return "string mode " + tok.Literal
+	case lexer.IDENT:
+		return "identifier " + tok.Literal
+	case lexer.VARIABLE:
+		return "variable $" + tok.Literal
+	case lexer.DURATION:
+		return "duration " + tok.Literal
+	}
+	if strings.HasPrefix(string(tok.Type), "MATCH_") || tok.Type == lexer.TRUE || tok.Type == lexer.FALSE || tok.Type == lexer.NULL {
+		return "keyword " + tok.Literal
+	}
+	return "'" + tok.Literal + "'"
+}
+
+// closest returns the candidate closest to the word by edit distance.
+//
+// Candidates that are too different from the word aren't suggested.
+func closest(word string, candidates []string) (string, bool) {
+	best := ""
+	bestDist := len(word)/3 + 1
+	for _, candidate := range candidates {
+		dist := editDistance(strings.ToLower(word), strings.ToLower(candidate))
+		if dist < bestDist {
+			best = candidate
+			bestDist = dist
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the edit distance between two strings.
+//
+// It's the Levenshtein distance that also counts a transposition
+// of two adjacent characters as a single edit, which is a common typo.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// Only the last three rows of the matrix are needed.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// SyntaxErrors is a list of errors in the pattern syntax.
+type SyntaxErrors []*SyntaxError
+
+// Error implements [error] interface.
+func (es SyntaxErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap makes errors.Is and errors.As work for every error in the list.
+func (es SyntaxErrors) Unwrap() []error {
+	res := make([]error, len(es))
+	for i, e := range es {
+		res[i] = e
+	}
+	return res
+}
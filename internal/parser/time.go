@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+const dateLayout = time.DateOnly
+
+// timeRef is a point in time that a timestamp is compared to.
+type timeRef struct {
+	// If true, the current time is used, as returned by the clock.
+	now   bool
+	value time.Time
+}
+
+// resolve returns the point in time the reference points to.
+func (r timeRef) resolve(clock func() time.Time) time.Time {
+	if r.now {
+		return clock()
+	}
+	return r.value
+}
+
+// timeConstraint is a restriction on a timestamp, like `within 5s of now` or `>= "2024-01-01"`.
+type timeConstraint struct {
+	// One of ">", ">=", "<", "<=", or "within".
+	op string
+	// The maximum allowed difference for the "within" operator.
+	delta time.Duration
+	ref   timeRef
+	// The constraint as written in the pattern, for error messages.
+	text string
+}
+
+// check returns an error if the timestamp doesn't satisfy the constraint.
+func (c timeConstraint) check(got time.Time, clock func() time.Time, date bool) valdo.Error {
+	ref := c.ref.resolve(clock)
+	if date {
+		ref = truncateToDate(ref)
+	}
+	var ok bool
+	switch c.op {
+	case ">":
+		ok = got.After(ref)
+	case ">=":
+		ok = !got.Before(ref)
+	case "<":
+		ok = got.Before(ref)
+	case "<=":
+		ok = !got.After(ref)
+	case "within":
+		diff := got.Sub(ref)
+		ok = -c.delta <= diff && diff <= c.delta
+	}
+	if ok {
+		return nil
+	}
+	return ErrTime{Got: got, Ref: ref, Expected: c.text, Date: date}
+}
+
+// timeType is a string containing an RFC 3339 timestamp or a date,
+// optionally restricted by time constraints.
+type timeType struct {
+	// If true, the value is a date without time, like "2024-01-01".
+	date  bool
+	cs    []timeConstraint
+	clock func() time.Time
+}
+
+// Validate implements [valdo.Validator].
+func (v timeType) Validate(data any) valdo.Error {
+	raw, ok := data.(string)
+	if !ok {
+		return valdo.ErrType{Got: typeName(data), Expected: "string"}
+	}
+	got, err := parseTime(raw, v.date)
+	if err != nil {
+		return ErrMalformed{Kind: v.name(), Err: err}
+	}
+	res := valdo.Errors{}
+	for _, c := range v.cs {
+		res.Add(c.check(got, v.clock, v.date))
+	}
+	return res.Flatten()
+}
+
+// Schema implements [valdo.Validator].
+func (v timeType) Schema() jsony.Object {
+	return jsony.Object{
+		jsony.Field{K: "type", V: jsony.SafeString("string")},
+		jsony.Field{K: "format", V: jsony.String(v.name())},
+	}
+}
+
+// name returns the name of the format, as used in JSON Schema.
+func (v timeType) name() string {
+	if v.date {
+		return "date"
+	}
+	return "date-time"
+}
+
+// parseTime parses an RFC 3339 timestamp or, if date is true, a date in "YYYY-MM-DD" format.
+func parseTime(raw string, date bool) (time.Time, error) {
+	if date {
+		return time.Parse(dateLayout, raw)
+	}
+	return time.Parse(time.RFC3339Nano, raw)
+}
+
+// toTime converts a variable value into a point in time.
+//
+// The value can be a [time.Time], an RFC 3339 timestamp, or a date.
+func toTime(value any) (time.Time, error) {
+	switch val := value.(type) {
+	case time.Time:
+		return val, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			return time.Parse(dateLayout, val)
+		}
+		return parsed, nil
+	default:
+		return time.Time{}, fmt.Errorf("expected time.Time or string, got %T", value)
+	}
+}
+
+// truncateToDate returns the midnight (in UTC) of the date of the given time.
+func truncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// An error indicating that the timestamp doesn't satisfy a time constraint.
+type ErrTime struct {
+	Format string
+	// The checked timestamp.
+	Got time.Time
+	// The point in time the timestamp was compared to.
+	Ref time.Time
+	// The constraint as written in the pattern, like "within 5s of now".
+	Expected string
+	// If true, the values are dates without time.
+	Date bool
+}
+
+// GetDefault implements [valdo.Error] interface.
+func (e ErrTime) GetDefault() valdo.Error {
+	return ErrTime{}
+}
+
+// SetFormat implements [valdo.Error] interface.
+func (e ErrTime) SetFormat(f string) valdo.Error {
+	e.Format = f
+	return e
+}
+
+// Error implements [error] interface.
+func (e ErrTime) Error() string {
+	f := e.Format
+	if f == "" {
+		f = "expected {expected} ({ref}), got {got} ({delta})"
+	}
+	layout := time.RFC3339Nano
+	if e.Date {
+		layout = dateLayout
+	}
+	r := strings.NewReplacer(
+		"{expected}", e.Expected,
+		"{ref}", e.Ref.Format(layout),
+		"{got}", e.Got.Format(layout),
+		"{delta}", formatDelta(e.Got.Sub(e.Ref), e.Date),
+	)
+	return r.Replace(f)
+}
+
+// formatDelta describes how much later or earlier a timestamp is.
+//
+// Dates are compared at day granularity, so for dates the delta is in days.
+func formatDelta(delta time.Duration, date bool) string {
+	suffix := " later"
+	if delta < 0 {
+		delta = -delta
+		suffix = " earlier"
+	}
+	if !date {
+		return delta.String() + suffix
+	}
+	days := int(delta.Round(24*time.Hour) / (24 * time.Hour))
+	if days == 1 {
+		return "1 day" + suffix
+	}
+	return strconv.Itoa(days) + " days" + suffix
+}
@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/orsinium-labs/jsony"
+	"github.com/orsinium-labs/testo/internal/lexer"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// parseVariable parses a reference to a variable, like `$id`, or a capture, like `$id = uuid`.
+//
+// A referenced variable matches only a value equal to the value of the variable.
+func (p *Parser) parseVariable() (valdo.Validator, error) {
+	tok := p.curToken
+	p.nextToken()
+	if p.curToken.Type == lexer.ASSIGN {
+		p.nextToken()
+		inner, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return captureType{name: tok.Literal, inner: inner, captures: p.captures}, nil
+	}
+	raw, found := p.config.Vars[tok.Literal]
+	if !found {
+		return nil, p.errorf(tok, "undefined variable $%s", tok.Literal)
+	}
+	value, err := literal(raw)
+	if err != nil {
+		return nil, p.errorf(tok, "invalid value of variable $%s: %v", tok.Literal, err)
+	}
+	return value, nil
+}
+
+// parseReference parses a reference to a named pattern, like `user`.
+func (p *Parser) parseReference() (valdo.Validator, error) {
+	tok := p.curToken
+	src, found := p.config.Defs[tok.Literal]
+	if !found {
+		return nil, p.unknownIdentifier(tok)
+	}
+	if slices.Contains(p.defining, tok.Literal) {
+		return nil, p.errorf(tok, "recursive reference to pattern %s", tok.Literal)
+	}
+	sub := New(lexer.New(src), p.config)
+	sub.openObjects = p.openObjects
+	sub.captures = p.captures
+	sub.defining = append(slices.Clone(p.defining), tok.Literal)
+	inner, err := sub.Parse()
+	if err != nil {
+		return nil, p.errorf(tok, "invalid pattern %s: %v", tok.Literal, err)
+	}
+	p.nextToken()
+	return named{name: tok.Literal, inner: inner, line: tok.Line, column: tok.Column}, nil
+}
+
+// captures delivers the values matched by captures to the sink.
+//
+// It's shared by all captures and unordered arrays of the pattern.
+type captures struct {
+	sink func(name string, value any)
+	// If greater than zero, the values aren't delivered because the items
+	// of unordered arrays are being tried against each other.
+	muted int
+}
+
+// report delivers the captured value to the sink unless muted.
+func (c *captures) report(name string, value any) {
+	if c != nil && c.muted == 0 {
+		c.sink(name, value)
+	}
+}
+
+// captureType reports the value matched by the inner validator.
+type captureType struct {
+	name     string
+	inner    valdo.Validator
+	captures *captures
+}
+
+// Validate implements [valdo.Validator].
+func (v captureType) Validate(data any) valdo.Error {
+	err := v.inner.Validate(data)
+	if err == nil {
+		v.captures.report(v.name, data)
+	}
+	return err
+}
+
+// Schema implements [valdo.Validator].
+func (v captureType) Schema() jsony.Object {
+	return v.inner.Schema()
+}
+
+// named is a named pattern referenced at the given position.
+//
+// The named pattern has its own source, so positions and fragments of errors
+// inside of it are replaced by the position and the name of the reference.
+type named struct {
+	name   string
+	inner  valdo.Validator
+	line   int
+	column int
+}
+
+// Validate implements [valdo.Validator].
+func (v named) Validate(data any) valdo.Error {
+	err := v.inner.Validate(data)
+	if err == nil {
+		return nil
+	}
+	return relocate(err, v.line, v.column, v.name)
+}
+
+// Schema implements [valdo.Validator].
+func (v named) Schema() jsony.Object {
+	return v.inner.Schema()
+}
+
+// relocate replaces the pattern position and fragment in all [ErrAt] errors in the tree.
+func relocate(err valdo.Error, line, column int, fragment string) valdo.Error {
+	if e, ok := err.(ErrAt); ok {
+		e.Line = line
+		e.Column = column
+		e.Fragment = fragment
+		err = e
+	}
+	wrapper, ok := err.(interface {
+		Map(f func(valdo.Error) valdo.Error) valdo.Error
+	})
+	if !ok {
+		return err
+	}
+	return wrapper.Map(func(e valdo.Error) valdo.Error {
+		return relocate(e, line, column, fragment)
+	})
+}
+
+// literal creates a validator matching only a value equal to the given one.
+//
+// Numbers of any supported type are compared exactly. Other Go values, like structs,
+// are converted to the JSON data model by encoding and decoding them, like the inputs.
+func literal(value any) (valdo.Validator, error) {
+	if rat, ok := ToRat(value); ok {
+		return numConst{value: rat, literal: formatRat(rat)}, nil
+	}
+	switch val := value.(type) {
+	case nil:
+		return valdo.Null(), nil
+	case string:
+		return valdo.Const(val), nil
+	case bool:
+		return valdo.BoolConst(val), nil
+	case []any:
+		items := make([]valdo.Validator, 0, len(val))
+		for _, item := range val {
+			v, err := literal(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return tupleType{items: items}, nil
+	case map[string]any:
+		props := make([]property, 0, len(val))
+		for _, key := range SortedKeys(val) {
+			v, err := literal(val[key])
+			if err != nil {
+				return nil, err
+			}
+			// Escape keys that would be interpreted as regular expressions.
+			if strings.HasPrefix(key, "^") {
+				key = "^" + key
+			}
+			prop, err := newProperty(key, v)
+			if err != nil {
+				return nil, err
+			}
+			props = append(props, prop)
+		}
+		return objectType{props: props}, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := DecodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	return literal(normalized)
+}
@@ -0,0 +1,101 @@
+package testo_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/orsinium-labs/testo"
+)
+
+type celsius float64
+
+func (c celsius) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{"value": float64(c), "unit": "C"})
+}
+
+type reading struct {
+	ID     *big.Int          `json:"id"`
+	At     time.Time         `json:"at"`
+	Temp   celsius           `json:"temp"`
+	Tags   []string          `json:"tags,omitempty"`
+	Counts map[string]int    `json:"counts"`
+	Note   string            `json:"-"`
+	Inner  *reading          `json:"inner,omitempty"`
+	Extra  map[string]string `json:"extra,omitempty"`
+}
+
+func TestValidate_GoValues(t *testing.T) {
+	id, _ := new(big.Int).SetString("12345678901234567890", 10)
+	value := reading{
+		ID:     id,
+		At:     time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+		Temp:   21.5,
+		Counts: map[string]int{"a": 1},
+		Note:   "hidden",
+	}
+	pattern := `{
+		"id": 12345678901234567890,
+		"at": datetime(>= "2024-01-01"),
+		"temp": {"value": 21.5, "unit": "C"},
+		"counts": {"a": 1}
+	}`
+	if err := testo.Validate(value, pattern); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testo.Assert(t, value, pattern)
+	testo.Assert(t, &value, pattern)
+	testo.Assert(t, []reading{value}, `[`+pattern+`]`)
+}
+
+func TestValidate_SameAsJSON(t *testing.T) {
+	value := reading{
+		ID:   big.NewInt(-1),
+		Temp: 0.1,
+		Tags: []string{"x", "y"},
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pattern := range []string{
+		`{"id": uint, "at": date, "temp": {"value": 0.1, "unit": "F"}, "tags": [string], "counts": {}}`,
+		`{"id": int, "at": string, "temp": object, "tags": strings, "counts": null, "note": string}`,
+		`{"id": int, "temp": {"value": float(0.2..)}}`,
+	} {
+		goErr := testo.Validate(value, pattern)
+		jsonErr := testo.ValidateJSON(raw, pattern)
+		if goErr == nil || jsonErr == nil {
+			t.Fatalf("expected errors for `%s`, got %v and %v", pattern, goErr, jsonErr)
+		}
+		if goErr.Error() != jsonErr.Error() {
+			t.Fatalf("different errors for `%s`:\n%v\n%v", pattern, goErr, jsonErr)
+		}
+	}
+}
+
+func TestValidate_NotMarshalable(t *testing.T) {
+	if testo.Validate(map[string]any{"f": func() {}}, `{"f": any}`) == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestWithVar_GoValues(t *testing.T) {
+	if err := testo.ValidateJSON(`0.1`, `$x`, testo.WithVar("x", 0.1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := testo.ValidateJSON(`{"temp": {"value": 21.5, "unit": "C"}}`, `{"temp": $t}`, testo.WithVar("t", celsius(21.5))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tags := []string{"a", "b"}
+	if err := testo.ValidateJSON(`["a", "b"]`, `$tags`, testo.WithVar("tags", tags)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := testo.ValidateJSON(`["a"]`, `$tags`, testo.WithVar("tags", tags)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := testo.Compile(`$ch`, testo.WithVar("ch", make(chan int))); err == nil {
+		t.Fatal("expected an error for a value that can't be encoded")
+	}
+}
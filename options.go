@@ -0,0 +1,151 @@
+package testo
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/orsinium-labs/testo/internal/parser"
+)
+
+// Option customizes how the pattern is matched.
+type Option func(*options)
+
+// options are the settings of a [Pattern] collected from the given [Option] values.
+type options struct {
+	// Settings that affect parsing and validation.
+	config parser.Config
+	// The maximum number of reported mismatches, or 0 if not limited.
+	maxErrors int
+	// JSON Pointers of values whose mismatches aren't reported.
+	ignore []string
+}
+
+// Use the given function to get the current time for `now` in time constraints.
+//
+// By default, [time.Now] is used.
+func WithClock(clock func() time.Time) Option {
+	return func(o *options) {
+		o.config.Clock = clock
+	}
+}
+
+// Define a variable that can be referenced in the pattern, like `$start`.
+//
+// A referenced variable matches only a value equal to the value of the variable.
+// The value is converted to the JSON data model the same way as the inputs,
+// so it can be any Go value, like a number, a struct, or a slice.
+// In time constraints, like `datetime(after $start)`, the value must be either
+// [time.Time] or a string with an RFC 3339 timestamp or a date.
+//
+// A variable can also be set by the pattern itself, like `$id = uuid`,
+// capturing the matched value. See [Asserter] for using captured values
+// in the following assertions.
+func WithVar(name string, value any) Option {
+	return func(o *options) {
+		vars := make(map[string]any, len(o.config.Vars)+1)
+		for k, v := range o.config.Vars {
+			vars[k] = v
+		}
+		vars[name] = value
+		o.config.Vars = vars
+	}
+}
+
+// Define a named pattern that can be referenced in the pattern by name.
+//
+// For example, after Define("user", `{"name": string}`), the pattern `[user, user]`
+// matches an array of two users. The name must consist of ASCII letters and digits,
+// start with a letter, and not be a keyword. Mismatches inside of the named pattern
+// are reported at the reference, with the name as the pattern fragment.
+func Define(name, pattern string) Option {
+	return func(o *options) {
+		defs := make(map[string]string, len(o.config.Defs)+1)
+		for k, v := range o.config.Defs {
+			defs[k] = v
+		}
+		defs[name] = pattern
+		o.config.Defs = defs
+	}
+}
+
+// withCapture calls the function with the value matched by every capture, like `$id = uuid`.
+func withCapture(capture func(name string, value any)) Option {
+	return func(o *options) {
+		o.config.Capture = capture
+	}
+}
+
+// Allow properties not listed in the pattern in all objects.
+//
+// Without the option, an unexpected property is a mismatch.
+func OpenObjects() Option {
+	return func(o *options) {
+		o.config.OpenObjects = true
+	}
+}
+
+// Allow items of arrays in the pattern, like `[1, 2, 3]`, to be in any order.
+//
+// Every item in the pattern must match a distinct item of the array.
+func UnorderedArrays() Option {
+	return func(o *options) {
+		o.config.UnorderedArrays = true
+	}
+}
+
+// Don't report mismatches of the values at the given JSON Pointers or inside of them.
+//
+// A "*" reference token matches any property or array index, like in "/items/*/id".
+// For example, IgnorePaths("/meta/request_id") ignores the request ID,
+// including when it's missing or unexpected.
+func IgnorePaths(paths ...string) Option {
+	return func(o *options) {
+		o.ignore = append(slices.Clip(o.ignore), paths...)
+	}
+}
+
+// Match numbers in the pattern, like `3.14`, with values that differ by at most eps.
+//
+// It doesn't affect ranges, like `float(0..1)`.
+func NumericTolerance(eps float64) Option {
+	return func(o *options) {
+		tolerance, ok := parser.ToRat(math.Abs(eps))
+		if !ok {
+			tolerance = nil
+		}
+		o.config.Tolerance = tolerance
+	}
+}
+
+// Don't convert numbers between integers and floats.
+//
+// A number written with a fraction or an exponent is a float, otherwise it's an integer.
+// So, `int` doesn't match `13.0`, `float` doesn't match `13`, and `13` doesn't match `13.0`.
+// The same applies to numbers in strings: `floatstr` doesn't match `"13"`.
+func StrictTypes() Option {
+	return func(o *options) {
+		o.config.StrictTypes = true
+	}
+}
+
+// Report at most n mismatches.
+//
+// By default, up to 100 mismatches are reported. Zero or a negative number removes the limit.
+func MaxErrors(n int) Option {
+	return func(o *options) {
+		o.maxErrors = max(n, 0)
+	}
+}
+
+// defaultMaxErrors is the maximum number of reported mismatches if [MaxErrors] isn't used.
+const defaultMaxErrors = 100
+
+// makeOptions applies the given options to the default ones.
+func makeOptions(opts []Option) options {
+	o := options{maxErrors: defaultMaxErrors}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,125 @@
+package testo_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+func TestOpenObjects(t *testing.T) {
+	given := `{"id": 1, "meta": {"version": 2}}`
+	if testo.ValidateJSON(given, `{"id": int, "meta": {}}`) == nil {
+		t.Fatal("expected an error without the option")
+	}
+	if err := testo.ValidateJSON(given, `{"id": int, "meta": {}}`, testo.OpenObjects()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if testo.ValidateJSON(given, `{"name": string}`, testo.OpenObjects()) == nil {
+		t.Fatal("missing properties must be reported")
+	}
+}
+
+func TestUnorderedArrays(t *testing.T) {
+	given := `[3, {"id": 1}, "a"]`
+	pattern := `["a", 3, {"id": int}]`
+	if testo.ValidateJSON(given, pattern) == nil {
+		t.Fatal("expected an error without the option")
+	}
+	if err := testo.ValidateJSON(given, pattern, testo.UnorderedArrays()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A greedy matching would pair `int` with 1 and fail on `1`.
+	if err := testo.ValidateJSON(`[1, 2]`, `[int, 1]`, testo.UnorderedArrays()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := testo.ValidateJSON(`[1, 2, "x"]`, `[int, 1, 3]`, testo.UnorderedArrays())
+	if err == nil || err.Error() != "/2: invalid type: got string, expected number" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if testo.ValidateJSON(`[1, 1]`, `[1]`, testo.UnorderedArrays()) == nil {
+		t.Fatal("the length must be checked")
+	}
+}
+
+func TestUnorderedArrays_Capture(t *testing.T) {
+	a := testo.New(t, testo.UnorderedArrays())
+	a.Assert(`["a", "b"]`, `["b", $x = string]`)
+	if x, _ := a.Var("x"); x != "a" {
+		t.Fatalf("expected the item paired with the capture, got %v", x)
+	}
+	a.Assert(`[{"id": 2}, {"id": 1}]`, `[{"id": 1}, {"id": $id = int}]`)
+	if id, _ := a.Var("id"); id != json.Number("2") {
+		t.Fatalf("expected the item paired with the capture, got %v", id)
+	}
+}
+
+func TestIgnorePaths(t *testing.T) {
+	given := `{"meta": {"request_id": 42}, "items": [{"id": "a", "at": 1}, {"id": "b", "at": 2}]}`
+	pattern := `{"meta": {"request_id": string, "trace": string}, "items": [{"id": string}, {"id": string}]}`
+	err := testo.ValidateJSON(given, pattern,
+		testo.IgnorePaths("/meta/request_id", "/meta/trace"),
+		testo.IgnorePaths("/items/*/at"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = testo.ValidateJSON(given, pattern, testo.IgnorePaths("/meta"))
+	if err == nil || !strings.HasPrefix(err.Error(), "/items/0/at: ") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = testo.ValidateJSON(`{"payload": "{\"id\": 1}"}`, `{"payload": json({"id": string})}`, testo.IgnorePaths("/payload/id"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A parenthesis in a key isn't a marker of a decoded value.
+	err = testo.ValidateJSON(`{"f(x)": "a", "f": 1}`, `{"f(x)": int, "f": 1}`, testo.IgnorePaths("/f"))
+	if err == nil || !strings.HasPrefix(err.Error(), "/f(x): ") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNumericTolerance(t *testing.T) {
+	pattern := `{"price": 9.99, "count": 3}`
+	if testo.ValidateJSON(`{"price": 9.991, "count": 3}`, pattern) == nil {
+		t.Fatal("expected an error without the option")
+	}
+	if err := testo.ValidateJSON(`{"price": 9.991, "count": 3}`, pattern, testo.NumericTolerance(0.01)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := testo.ValidateJSON(`{"price": 10, "count": 3}`, pattern, testo.NumericTolerance(0.01)); err != nil {
+		t.Fatalf("the bound must be inclusive: %v", err)
+	}
+	err := testo.ValidateJSON(`{"price": 10.01, "count": 3}`, pattern, testo.NumericTolerance(0.01))
+	if err == nil || !strings.Contains(err.Error(), "9.99 ± 0.01") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStrictTypes(t *testing.T) {
+	tests := []struct {
+		given   string
+		pattern string
+	}{
+		{`13.0`, `int`},
+		{`1e2`, `uint`},
+		{`13`, `float`},
+		{`13.0`, `13`},
+		{`13`, `13.0`},
+		{`[1, 2.0]`, `ints`},
+		{`"13"`, `floatstr`},
+		{`"13.0"`, `intstr`},
+	}
+	for _, tt := range tests {
+		if err := testo.ValidateJSON(tt.given, tt.pattern); err != nil {
+			t.Fatalf("unexpected error for `%s` without the option: %v", tt.given, err)
+		}
+		if testo.ValidateJSON(tt.given, tt.pattern, testo.StrictTypes()) == nil {
+			t.Fatalf("expected an error for `%s` matched with `%s`", tt.given, tt.pattern)
+		}
+	}
+	if err := testo.Validate(map[string]any{"n": 13, "f": 1.5}, `{"n": int, "f": float}`, testo.StrictTypes()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package testo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/orsinium-labs/testo/internal/parser"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// Pattern is a compiled pattern.
+//
+// Compile the pattern once and reuse it to avoid parsing the same pattern
+// for every validated value, for example, in table-driven tests.
+//
+// A Pattern is immutable and safe for concurrent use by multiple goroutines,
+// including parallel subtests started with [testing.T.Parallel].
+type Pattern struct {
+	src       string
+	validator valdo.Validator
+	// The maximum number of reported mismatches, or 0 if not limited.
+	maxErrors int
+	// JSON Pointers of values that aren't validated.
+	ignore []string
+}
+
+// Compile parses the pattern.
+func Compile(src string, opts ...Option) (*Pattern, error) {
+	o := makeOptions(opts)
+	validator, err := parser.Parse(src, o.config)
+	if err != nil {
+		return nil, newSyntaxError(err, src)
+	}
+	p := &Pattern{
+		src:       src,
+		validator: validator,
+		maxErrors: o.maxErrors,
+		ignore:    o.ignore,
+	}
+	return p, nil
+}
+
+// MustCompile is like [Compile] but panics if the pattern cannot be parsed.
+//
+// It simplifies initialization of global variables holding compiled patterns.
+func MustCompile(src string, opts ...Option) *Pattern {
+	p, err := Compile(src, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("testo: Compile(%q): %v", src, err))
+	}
+	return p
+}
+
+// String returns the source text of the pattern.
+func (p *Pattern) String() string {
+	return p.src
+}
+
+// Validator returns the [valdo.Validator] the pattern is compiled into.
+func (p *Pattern) Validator() valdo.Validator {
+	return p.validator
+}
+
+// Fail tests if the given input doesn't match the pattern.
+//
+// See [Assert] for the supported types of input.
+func (p *Pattern) Assert(t testing.TB, given any) {
+	t.Helper()
+	if f := p.check(given); f != nil {
+		t.Fatal(f.message)
+	}
+}
+
+// Fail tests if the values selected from the given input don't match the pattern.
+//
+// See [AssertAt] for the supported selectors.
+func (p *Pattern) AssertAt(t testing.TB, given any, selector string) {
+	t.Helper()
+	if f := p.checkAt(given, selector); f != nil {
+		t.Fatal(f.message)
+	}
+}
+
+// Mark the test as failed if the given input doesn't match the pattern.
+//
+// Unlike [Pattern.Assert], the test continues. Returns true if the input matches.
+func (p *Pattern) Check(t testing.TB, given any) bool {
+	t.Helper()
+	f := p.check(given)
+	if f != nil {
+		t.Error(f.message)
+	}
+	return f == nil
+}
+
+// failure describes a failed assertion.
+type failure struct {
+	// The full description of the failure.
+	message string
+	// The first line of the description, like "age: must be greater than or equal to 0".
+	summary string
+	// The number of mismatches, or 0 if the input couldn't be read.
+	mismatches int
+}
+
+// check validates the input and describes the failure if it doesn't match.
+//
+// Returns nil if the input matches the pattern.
+func (p *Pattern) check(given any) *failure {
+	in, err := readInput(given)
+	if err != nil {
+		return newFailure(fmt.Sprintf("failed to read input: %v", err))
+	}
+	return p.explain(in, p.mismatches(in))
+}
+
+// checkAt is like [Pattern.check] but validates only the nodes of the input matched by the selector.
+func (p *Pattern) checkAt(given any, selector string) *failure {
+	in, err := readInput(given)
+	if err != nil {
+		return newFailure(fmt.Sprintf("failed to read input: %v", err))
+	}
+	mErr, err := p.mismatchesAt(in, selector)
+	if err != nil {
+		return newFailure(err.Error())
+	}
+	return p.explain(in, mErr)
+}
+
+// newFailure creates a failure that isn't caused by mismatches, like an invalid input.
+func newFailure(msg string) *failure {
+	return &failure{message: msg, summary: msg}
+}
+
+// explain describes the mismatches found in the input.
+//
+// Returns nil if there are no mismatches.
+func (p *Pattern) explain(in input, mErr *MismatchError) *failure {
+	if mErr == nil {
+		return nil
+	}
+	summary := mErr.Error()
+	if lines := mErr.report(); len(lines) > 0 {
+		summary = lines[0]
+	}
+	return &failure{
+		message:    failureMessage(in.value, mErr, p.src, useColor()),
+		summary:    summary,
+		mismatches: len(mErr.Mismatches) + mErr.Omitted,
+	}
+}
+
+// Validate that the given Go value matches the pattern.
+//
+// The value is validated as if it was encoded into JSON with [encoding/json.Marshal] and decoded back.
+func (p *Pattern) Validate(given any) error {
+	in, err := normalizeInput(given)
+	if err != nil {
+		return err
+	}
+	return p.validate(in)
+}
+
+// Validate that the given JSON message matches the pattern.
+//
+// Mismatches in the returned error include their positions in the message.
+func (p *Pattern) ValidateJSON(given []byte) error {
+	in, err := decodeInput(given, inputName)
+	if err != nil {
+		return err
+	}
+	return p.validate(in)
+}
+
+// validate checks the already decoded input.
+func (p *Pattern) validate(in input) error {
+	mErr := p.mismatches(in)
+	if mErr == nil {
+		return nil
+	}
+	return mErr
+}
+
+// mismatches validates the input and describes the found mismatches.
+//
+// Returns nil if the input matches the pattern.
+func (p *Pattern) mismatches(in input) *MismatchError {
+	vErr := p.validator.Validate(in.value)
+	if vErr == nil {
+		return nil
+	}
+	mErr := newMismatchError(vErr, p.maxErrors, p.ignore)
+	if mErr == nil {
+		return nil
+	}
+	mErr.locate(in)
+	return mErr
+}
+
+// mismatchesAt validates every node of the input matched by the selector.
+//
+// Paths of the mismatches start from the root of the input, not from the selected node.
+// It's an error if the selector is invalid or matches nothing.
+func (p *Pattern) mismatchesAt(in input, selector string) (*MismatchError, error) {
+	nodes, err := selectNodes(in.value, selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %v", selector, err)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("selector %q matched nothing", selector)
+	}
+	errs := valdo.Errors{}
+	for _, n := range nodes {
+		vErr := p.validator.Validate(n.value)
+		if vErr != nil {
+			errs.Add(n.wrap(vErr))
+		}
+	}
+	vErr := errs.Flatten()
+	if vErr == nil {
+		return nil, nil
+	}
+	mErr := newMismatchError(vErr, p.maxErrors, p.ignore)
+	if mErr == nil {
+		return nil, nil
+	}
+	mErr.selector = selector
+	mErr.locate(in)
+	return mErr, nil
+}
@@ -0,0 +1,56 @@
+package testo_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+func TestCompile(t *testing.T) {
+	p, err := testo.Compile(`{"name": string, "age": uint}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.String() != `{"name": string, "age": uint}` {
+		t.Fatalf("unexpected source: %s", p.String())
+	}
+	if err := p.ValidateJSON([]byte(`{"name": "aragorn", "age": 87}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.ValidateJSON([]byte(`{"name": "aragorn", "age": -1}`)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := p.ValidateJSON([]byte(`{"name": `)); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestCompile_BadPattern(t *testing.T) {
+	_, err := testo.Compile(`{"name": }`)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestMustCompile_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	testo.MustCompile(`[1, `)
+}
+
+func TestPattern_Parallel(t *testing.T) {
+	p := testo.MustCompile(`{"id": uint, "tags": strings}`)
+	for i := range 20 {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			p.Assert(t, fmt.Sprintf(`{"id": %d, "tags": ["a", "b"]}`, i))
+			if err := p.Validate(map[string]any{"id": "x"}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
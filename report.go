@@ -0,0 +1,135 @@
+package testo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// report describes every mismatch in a separate line, grouping repeated failures in array items.
+//
+// Mismatches that differ only by the index of an array item are reported
+// in one line with the range of indices, like `items[3..17].price`.
+// If an item is nested in several arrays, the innermost index is grouped.
+func (e *MismatchError) report() []string {
+	type group struct {
+		segments []Segment
+		// The position of the grouped index in segments, or -1 if there is none.
+		at      int
+		indices []int
+		message string
+		// The position in the input of the first mismatch in the group, like "input:3:12: ".
+		location string
+	}
+	groups := make([]*group, 0)
+	byKey := make(map[string]*group)
+	for _, m := range e.Mismatches {
+		segments := m.Segments
+		at := -1
+		for i, s := range segments {
+			if s.Kind == SegmentIndex {
+				at = i
+			}
+		}
+		// The pattern position isn't a part of the key, so that items
+		// of a tuple with the same error are grouped as well.
+		key := formatSegments(segments, at, "*") + "\x00" + m.Err.Error()
+		g, found := byKey[key]
+		if !found {
+			g = &group{segments: segments, at: at, message: m.message()}
+			if m.InputLine > 0 {
+				g.location = fmt.Sprintf("%s:%d:%d: ", e.input, m.InputLine, m.InputColumn)
+			}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		if at >= 0 {
+			g.indices = append(g.indices, segments[at].Index)
+		}
+	}
+
+	lines := make([]string, 0, len(groups)+1)
+	for _, g := range groups {
+		path := formatSegments(g.segments, g.at, formatIndices(g.indices))
+		if path == "" {
+			lines = append(lines, g.location+g.message)
+		} else {
+			lines = append(lines, g.location+path+": "+g.message)
+		}
+	}
+	if e.Omitted > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more mismatches (see testo.MaxErrors)", e.Omitted))
+	}
+	return lines
+}
+
+// formatSegments formats the path in the dotted notation, like `items[3].price`.
+//
+// Values decoded from strings and errors in object keys are marked in parentheses,
+// like `payload(json).id` or `(json).id` at the root, and `users.bob(key)`.
+// The index at the given position is replaced by the given text.
+func formatSegments(segments []Segment, at int, replacement string) string {
+	var b strings.Builder
+	for i, s := range segments {
+		switch {
+		case i == at:
+			b.WriteString("[" + replacement + "]")
+		case s.Kind == SegmentIndex:
+			b.WriteString("[" + strconv.Itoa(s.Index) + "]")
+		case s.Kind == SegmentDecoded:
+			b.WriteString("(" + s.Name + ")")
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(s.Name)
+			if s.Kind == SegmentKey {
+				b.WriteString("(key)")
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatIndices formats the list of indices, merging consecutive ones into ranges, like `1..3,7`.
+func formatIndices(indices []int) string {
+	parts := make([]string, 0, 1)
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && indices[j+1] == indices[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, strconv.Itoa(indices[i]))
+		} else {
+			parts = append(parts, strconv.Itoa(indices[i])+".."+strconv.Itoa(indices[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// failureMessage describes the mismatches for a failed assertion.
+//
+// It includes the list of mismatches, the excerpt of the input with the mismatched
+// values marked, and the pattern lines the values were matched against.
+func failureMessage(given any, e *MismatchError, src string, color bool) string {
+	var b strings.Builder
+	if e.selector == "" {
+		b.WriteString("validation error:")
+	} else {
+		fmt.Fprintf(&b, "validation error at `%s`:", e.selector)
+	}
+	for _, line := range e.report() {
+		b.WriteString("\n  ")
+		b.WriteString(line)
+	}
+	b.WriteString("\n\ninput:\n")
+	b.WriteString(renderExcerpt(given, e.Mismatches, color))
+	snippets := renderSnippets(src, e.Mismatches, color)
+	if snippets != "" {
+		b.WriteString("\n\npattern:\n")
+		b.WriteString(snippets)
+	}
+	return b.String()
+}
@@ -0,0 +1,187 @@
+package testo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReport_Grouping(t *testing.T) {
+	items := make([]string, 0)
+	itemPatterns := make([]string, 0)
+	for i := range 20 {
+		price := "1.5"
+		if i >= 3 && i <= 17 && i != 10 {
+			price = `"free"`
+		}
+		name := fmt.Sprintf(`"item %d"`, i)
+		if i == 10 || i == 12 {
+			name = "null"
+		}
+		items = append(items, fmt.Sprintf(`{"price": %s, "name": %s}`, price, name))
+		itemPatterns = append(itemPatterns, `{"price": float, "name": string}`)
+	}
+	given := `{"items": [` + strings.Join(items, ", ") + `], "total": "1"}`
+	pattern := `{"items": [` + strings.Join(itemPatterns, ", ") + `], "total": int}`
+	err := ValidateJSON(given, pattern)
+	var mErr *MismatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("expected MismatchError, got %v", err)
+	}
+	if len(mErr.Mismatches) != 17 {
+		t.Fatalf("expected 17 mismatches, got %d", len(mErr.Mismatches))
+	}
+	lines := mErr.report()
+	expected := []string{
+		"input:1:124: items[3..9,11..17].price: invalid type: got string, expected number (pattern `float` at 1:124)",
+		"input:1:396: items[10,12].name: invalid type: got null, expected string (pattern `string` at 1:377)",
+		"input:1:744: total: invalid type: got string, expected integer (pattern `int` at 1:702)",
+	}
+	if !slices.Equal(lines, expected) {
+		t.Fatalf("unexpected report:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestReport_MaxErrors(t *testing.T) {
+	err := ValidateJSON(`["a", "b", "c", "d"]`, `[int, int, int, int]`, MaxErrors(2))
+	var mErr *MismatchError
+	if !errors.As(err, &mErr) {
+		t.Fatalf("expected MismatchError, got %v", err)
+	}
+	if len(mErr.Mismatches) != 2 || mErr.Omitted != 2 {
+		t.Fatalf("unexpected number of mismatches: %d, omitted %d", len(mErr.Mismatches), mErr.Omitted)
+	}
+	lines := mErr.report()
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "input:1:2: [0..1]: ") || !strings.HasPrefix(lines[1], "... and 2 more") {
+		t.Fatalf("unexpected report:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestReport_Decoded(t *testing.T) {
+	// {"alg":"HS256","typ":"JWT"} . {"sub":"0b6e6b2e-1c2e-4c4b-9b0a-0b6e6b2e1c2e","exp":1700000000,"iat":1690000000}
+	token := `eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.` +
+		`eyJzdWIiOiIwYjZlNmIyZS0xYzJlLTRjNGItOWIwYS0wYjZlNmIyZTFjMmUiLCJleHAiOjE3MDAwMDAwMDAsImlhdCI6MTY5MDAwMDAwMH0.` +
+		`c2lnbmF0dXJl`
+	tests := []struct {
+		given, pattern string
+		prefix         string
+	}{
+		{`"{\"a\": \"x\"}"`, `json({"a": int})`, "input:1:1: (json).a: "},
+		{`"` + token + `"`, `jwt({"sub": int})`, "input:1:1: (jwt).sub: "},
+		{`{"p": "{\"a\": [\"x\"]}"}`, `{"p": json({"a": [int]})}`, "input:1:7: p(json).a[0]: "},
+		{`{"f(x)": "a"}`, `{"f(x)": int}`, "input:1:10: f(x): "},
+	}
+	for _, tt := range tests {
+		var mErr *MismatchError
+		if !errors.As(ValidateJSON(tt.given, tt.pattern), &mErr) {
+			t.Fatalf("expected MismatchError for `%s`", tt.pattern)
+		}
+		lines := mErr.report()
+		if len(lines) != 1 || !strings.HasPrefix(lines[0], tt.prefix) {
+			t.Errorf("unexpected report for `%s`:\n%s", tt.pattern, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestFormatIndices(t *testing.T) {
+	tests := []struct {
+		given    []int
+		expected string
+	}{
+		{[]int{3}, "3"},
+		{[]int{3, 4}, "3..4"},
+		{[]int{1, 2, 3, 7, 9, 10}, "1..3,7,9..10"},
+	}
+	for _, tt := range tests {
+		got := formatIndices(tt.given)
+		if got != tt.expected {
+			t.Errorf("formatIndices(%v) = %q, expected %q", tt.given, got, tt.expected)
+		}
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	given := `{"name": "aragorn", "age": -3, "tags": ["a", 1, "c"], "extra": true, "address": {"city": "Bree"}}`
+	pattern := "{\n  \"name\": string,\n  \"age\": uint,\n  \"tags\": strings,\n  \"email\": string,\n  \"address\": {\"city\": string}\n}"
+	in, err := readInput(given)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := MustCompile(pattern)
+	var mErr *MismatchError
+	if !errors.As(p.ValidateJSON([]byte(given)), &mErr) {
+		t.Fatal("expected MismatchError")
+	}
+	expected := strings.Join([]string{
+		"validation error:",
+		"  input:1:28: age: must be greater than or equal to 0 (pattern `uint` at 3:10)",
+		"  input:1:46: tags[1]: invalid type: got integer, expected string (pattern `strings` at 4:11)",
+		"  input:1:1: email: email is required but not found (pattern `string` at 5:12)",
+		"  input:1:64: extra: unexpected property: extra",
+		"",
+		"input:",
+		"{",
+		"  ...",
+		`  "age": -3,  <-- expected uint, got -3`,
+		`  "email": <missing>,  <-- missing, expected string`,
+		`  "extra": true,  <-- unexpected property`,
+		`  ...`,
+		`  "tags": [`,
+		`    ...`,
+		`    1,  <-- expected strings, got 1`,
+		`    ...`,
+		`  ]`,
+		"}",
+		"",
+		"pattern:",
+		`3 |   "age": uint,`,
+		`  |          ^`,
+		`4 |   "tags": strings,`,
+		`  |           ^`,
+		`5 |   "email": string,`,
+		`  |            ^`,
+	}, "\n")
+	got := failureMessage(in.value, mErr, pattern, false)
+	if got != expected {
+		t.Fatalf("unexpected message:\n%s", got)
+	}
+}
+
+func TestRenderExcerpt_Segments(t *testing.T) {
+	tests := []struct {
+		given, pattern string
+		expected       string
+	}{
+		{
+			`{"f(x)": "a"}`,
+			`{"f(x)": int}`,
+			"{\n  \"f(x)\": \"a\"  <-- expected int, got \"a\"\n}",
+		},
+		{
+			`"{\"a\": \"x\"}"`,
+			`json({"a": int})`,
+			`"{\"a\": \"x\"}"  <-- (json) invalid type: got string, expected integer (pattern ` + "`int`" + ` at 1:12)`,
+		},
+		{
+			`{"payload": "{\"a\": \"x\"}"}`,
+			`{"payload": json({"a": int})}`,
+			"{\n  \"payload\": \"{\\\"a\\\": \\\"x\\\"}\"  <-- (json) invalid type: got string, expected integer (pattern `int` at 1:24)\n}",
+		},
+	}
+	for _, tt := range tests {
+		in, err := readInput(tt.given)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var mErr *MismatchError
+		if !errors.As(ValidateJSON(tt.given, tt.pattern), &mErr) {
+			t.Fatalf("expected MismatchError for `%s`", tt.pattern)
+		}
+		got := renderExcerpt(in.value, mErr.Mismatches, false)
+		if got != tt.expected {
+			t.Errorf("unexpected excerpt for `%s`:\n%s", tt.pattern, got)
+		}
+	}
+}
@@ -0,0 +1,537 @@
+package testo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/orsinium-labs/testo/internal/lexer"
+	"github.com/orsinium-labs/testo/internal/parser"
+	"github.com/orsinium-labs/valdo/valdo"
+)
+
+// step is a reference token in the path from the root of the input to a selected node.
+type step struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// node is a value selected from the input.
+type node struct {
+	value any
+	steps []step
+}
+
+// child returns the node for the property or the item of the node.
+func (n node) child(s step, value any) node {
+	steps := make([]step, len(n.steps), len(n.steps)+1)
+	copy(steps, n.steps)
+	return node{value: value, steps: append(steps, s)}
+}
+
+// children returns items of the array or values of the object, sorted by keys.
+func (n node) children() []node {
+	switch val := n.value.(type) {
+	case []any:
+		res := make([]node, 0, len(val))
+		for i, item := range val {
+			res = append(res, n.child(step{index: i, isIndex: true}, item))
+		}
+		return res
+	case map[string]any:
+		res := make([]node, 0, len(val))
+		for _, key := range parser.SortedKeys(val) {
+			res = append(res, n.child(step{name: key}, val[key]))
+		}
+		return res
+	default:
+		return nil
+	}
+}
+
+// member returns the node for the property with the given name, if the node is an object that has it.
+func (n node) member(name string) (node, bool) {
+	obj, ok := n.value.(map[string]any)
+	if !ok {
+		return node{}, false
+	}
+	value, found := obj[name]
+	if !found {
+		return node{}, false
+	}
+	return n.child(step{name: name}, value), true
+}
+
+// item returns the node for the array item at the index.
+//
+// A negative index counts from the end of the array.
+func (n node) item(index int) (node, bool) {
+	arr, ok := n.value.([]any)
+	if !ok {
+		return node{}, false
+	}
+	if index < 0 {
+		index += len(arr)
+	}
+	if index < 0 || index >= len(arr) {
+		return node{}, false
+	}
+	return n.child(step{index: index, isIndex: true}, arr[index]), true
+}
+
+// wrap attaches the path to the node to the validation error of the node value.
+func (n node) wrap(err valdo.Error) valdo.Error {
+	for i := len(n.steps) - 1; i >= 0; i-- {
+		s := n.steps[i]
+		if s.isIndex {
+			err = valdo.ErrIndex{Index: s.index, Err: err}
+		} else {
+			err = valdo.ErrProperty{Name: s.name, Err: err}
+		}
+	}
+	return err
+}
+
+// selectNodes returns the values in the input matched by the selector.
+//
+// The selector is either a JSON Pointer (RFC 6901), like "/data/items/0",
+// or a JSONPath expression, like "$.data.items[?(@.id==3)]".
+// See [pathParser] for the supported subset of JSONPath.
+func selectNodes(value any, selector string) ([]node, error) {
+	root := node{value: value}
+	if selector == "" || selector[0] == '/' {
+		return selectPointer(root, selector), nil
+	}
+	if selector[0] == '$' {
+		sp := &pathParser{src: selector, pos: 1}
+		return sp.parse([]node{root})
+	}
+	return nil, errors.New(`must be a JSON Pointer starting with "/" or a JSONPath starting with "$"`)
+}
+
+// selectPointer returns the value at the JSON Pointer, or nothing if there is no such value.
+func selectPointer(root node, pointer string) []node {
+	if pointer == "" {
+		return []node{root}
+	}
+	current := root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		var found bool
+		if _, isArray := current.value.([]any); isArray {
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || strings.HasPrefix(token, "+") {
+				return nil
+			}
+			current, found = current.item(index)
+		} else {
+			current, found = current.member(token)
+		}
+		if !found {
+			return nil
+		}
+	}
+	return []node{current}
+}
+
+// pathParser selects nodes with a JSONPath expression.
+//
+// The supported segments are:
+//
+//   - `.name` and `['name']`: the property of an object;
+//   - `[0]` and `[-1]`: the array item, counting from the end if negative;
+//   - `['a','b']` and `[0,1]`: several properties or items;
+//   - `.*` and `[*]`: all items of an array or values of an object;
+//   - `..name`, `..*`, and `..[0]`: the segment applied to the node and all its descendants;
+//   - `[?(@.price < 10)]`: items or values for which the filter is true.
+//
+// A filter compares a path relative to the item, like `@.user.id` or `@['id']`,
+// with a number, a string, `true`, `false`, or `null` using `==`, `!=`, `<`, `<=`, `>`, or `>=`.
+// Without a comparison, like `[?(@.id)]`, the filter checks that the path exists.
+type pathParser struct {
+	src string
+	pos int
+}
+
+// parse applies all segments of the path to the nodes.
+func (sp *pathParser) parse(nodes []node) ([]node, error) {
+	for sp.pos < len(sp.src) {
+		var err error
+		switch {
+		case strings.HasPrefix(sp.src[sp.pos:], ".."):
+			sp.pos += 2
+			nodes = descendants(nodes)
+			if sp.peek() == '[' {
+				continue
+			}
+			nodes, err = sp.dotMember(nodes)
+		case sp.peek() == '.':
+			sp.pos++
+			nodes, err = sp.dotMember(nodes)
+		case sp.peek() == '[':
+			sp.pos++
+			nodes, err = sp.bracket(nodes)
+		default:
+			err = sp.errorf("expected '.' or '['")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// dotMember applies the segment following a dot, like `.name` or `.*`.
+func (sp *pathParser) dotMember(nodes []node) ([]node, error) {
+	if sp.peek() == '*' {
+		sp.pos++
+		return allChildren(nodes), nil
+	}
+	name := sp.name()
+	if name == "" {
+		return nil, sp.errorf("expected a property name")
+	}
+	return members(nodes, name), nil
+}
+
+// bracket applies the segment in brackets, like `[0]`, `['name']`, `[*]`, or `[?(...)]`.
+func (sp *pathParser) bracket(nodes []node) ([]node, error) {
+	sp.skipSpace()
+	var res []node
+	switch {
+	case sp.peek() == '*':
+		sp.pos++
+		res = allChildren(nodes)
+	case sp.peek() == '?':
+		sp.pos++
+		f, err := sp.filter()
+		if err != nil {
+			return nil, err
+		}
+		res = make([]node, 0)
+		for _, n := range allChildren(nodes) {
+			if f.match(n) {
+				res = append(res, n)
+			}
+		}
+	default:
+		res = make([]node, 0)
+		for {
+			sp.skipSpace()
+			s, err := sp.selector()
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				var child node
+				var found bool
+				if s.isIndex {
+					child, found = n.item(s.index)
+				} else {
+					child, found = n.member(s.name)
+				}
+				if found {
+					res = append(res, child)
+				}
+			}
+			sp.skipSpace()
+			if sp.peek() != ',' {
+				break
+			}
+			sp.pos++
+		}
+	}
+	sp.skipSpace()
+	if sp.peek() != ']' {
+		return nil, sp.errorf("expected ']'")
+	}
+	sp.pos++
+	return res, nil
+}
+
+// selector parses a quoted property name or an array index.
+func (sp *pathParser) selector() (step, error) {
+	ch := sp.peek()
+	if ch == '\'' || ch == '"' {
+		name, err := sp.quoted()
+		return step{name: name}, err
+	}
+	start := sp.pos
+	if ch == '-' {
+		sp.pos++
+	}
+	for lexer.IsDigit(sp.peek()) {
+		sp.pos++
+	}
+	index, err := strconv.Atoi(sp.src[start:sp.pos])
+	if err != nil {
+		sp.pos = start
+		return step{}, sp.errorf("expected an index or a quoted property name")
+	}
+	return step{index: index, isIndex: true}, nil
+}
+
+// filter parses a filter expression following `?`, like `(@.id == 3)`.
+func (sp *pathParser) filter() (filter, error) {
+	sp.skipSpace()
+	parens := sp.peek() == '('
+	if parens {
+		sp.pos++
+		sp.skipSpace()
+	}
+	if sp.peek() != '@' {
+		return filter{}, sp.errorf("expected '@'")
+	}
+	sp.pos++
+	var f filter
+	for {
+		if sp.peek() == '.' {
+			sp.pos++
+			name := sp.name()
+			if name == "" {
+				return filter{}, sp.errorf("expected a property name")
+			}
+			f.path = append(f.path, step{name: name})
+		} else if sp.peek() == '[' {
+			sp.pos++
+			sp.skipSpace()
+			s, err := sp.selector()
+			if err != nil {
+				return filter{}, err
+			}
+			sp.skipSpace()
+			if sp.peek() != ']' {
+				return filter{}, sp.errorf("expected ']'")
+			}
+			sp.pos++
+			f.path = append(f.path, s)
+		} else {
+			break
+		}
+	}
+	sp.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(sp.src[sp.pos:], op) {
+			sp.pos += len(op)
+			f.op = op
+			break
+		}
+	}
+	if f.op != "" {
+		sp.skipSpace()
+		value, err := sp.literal()
+		if err != nil {
+			return filter{}, err
+		}
+		f.value = value
+		sp.skipSpace()
+	}
+	if parens {
+		if sp.peek() != ')' {
+			return filter{}, sp.errorf("expected ')'")
+		}
+		sp.pos++
+	}
+	return f, nil
+}
+
+// literal parses a value to compare with in a filter.
+//
+// Numbers are returned as [*big.Rat] to be compared exactly.
+func (sp *pathParser) literal() (any, error) {
+	ch := sp.peek()
+	if ch == '\'' || ch == '"' {
+		return sp.quoted()
+	}
+	for _, kw := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(sp.src[sp.pos:], kw) {
+			sp.pos += len(kw)
+			switch kw {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			default:
+				return nil, nil
+			}
+		}
+	}
+	start := sp.pos
+	for sp.pos < len(sp.src) && strings.IndexByte("+-.eE0123456789", sp.src[sp.pos]) >= 0 {
+		sp.pos++
+	}
+	num, ok := new(big.Rat).SetString(sp.src[start:sp.pos])
+	if !ok || start == sp.pos {
+		sp.pos = start
+		return nil, sp.errorf("expected a number, a quoted string, true, false, or null")
+	}
+	return num, nil
+}
+
+// quoted parses a string in single or double quotes.
+//
+// A backslash escapes the next character.
+func (sp *pathParser) quoted() (string, error) {
+	quote := sp.src[sp.pos]
+	start := sp.pos
+	sp.pos++
+	var b strings.Builder
+	for sp.pos < len(sp.src) {
+		ch := sp.src[sp.pos]
+		sp.pos++
+		switch ch {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if sp.pos < len(sp.src) {
+				b.WriteByte(sp.src[sp.pos])
+				sp.pos++
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	sp.pos = start
+	return "", sp.errorf("unterminated string")
+}
+
+// name parses a property name following a dot.
+func (sp *pathParser) name() string {
+	start := sp.pos
+	for sp.pos < len(sp.src) && strings.IndexByte(".[]()=!<> ,", sp.src[sp.pos]) < 0 {
+		sp.pos++
+	}
+	return sp.src[start:sp.pos]
+}
+
+// peek returns the current character or 0 at the end of the path.
+func (sp *pathParser) peek() byte {
+	if sp.pos >= len(sp.src) {
+		return 0
+	}
+	return sp.src[sp.pos]
+}
+
+func (sp *pathParser) skipSpace() {
+	for sp.peek() == ' ' {
+		sp.pos++
+	}
+}
+
+// errorf creates an error pointing to the current position in the path.
+func (sp *pathParser) errorf(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if sp.pos >= len(sp.src) {
+		return fmt.Errorf("%s at the end", msg)
+	}
+	return fmt.Errorf("%s at offset %d, got %q", msg, sp.pos, sp.src[sp.pos])
+}
+
+// filter is a condition on a node, like `@.id == 3`.
+type filter struct {
+	// The path relative to the filtered node.
+	path []step
+	// The comparison operator, or empty if the filter only checks that the path exists.
+	op    string
+	value any
+}
+
+// match checks if the filter is true for the node.
+func (f filter) match(n node) bool {
+	current := n
+	for _, s := range f.path {
+		var found bool
+		if s.isIndex {
+			current, found = current.item(s.index)
+		} else {
+			current, found = current.member(s.name)
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.op == "" {
+		return true
+	}
+	return compare(current.value, f.op, f.value)
+}
+
+// compare compares the value in the input with the value from a filter.
+//
+// Numbers and strings can be compared with any operator,
+// other values only with `==` and `!=`. Values of different types are never equal.
+func compare(got any, op string, want any) bool {
+	cmp, ordered, comparable := 0, false, false
+	switch want := want.(type) {
+	case *big.Rat:
+		if num, ok := parser.ToRat(got); ok {
+			cmp, ordered, comparable = num.Cmp(want), true, true
+		}
+	case string:
+		if str, ok := got.(string); ok {
+			cmp, ordered, comparable = strings.Compare(str, want), true, true
+		}
+	case bool:
+		if b, ok := got.(bool); ok {
+			comparable = true
+			if b != want {
+				cmp = 1
+			}
+		}
+	case nil:
+		comparable = true
+		if got != nil {
+			cmp = 1
+		}
+	}
+	switch op {
+	case "==":
+		return comparable && cmp == 0
+	case "!=":
+		return !comparable || cmp != 0
+	case "<":
+		return ordered && cmp < 0
+	case "<=":
+		return ordered && cmp <= 0
+	case ">":
+		return ordered && cmp > 0
+	case ">=":
+		return ordered && cmp >= 0
+	default:
+		return false
+	}
+}
+
+// members returns the properties with the given name of all nodes.
+func members(nodes []node, name string) []node {
+	res := make([]node, 0, len(nodes))
+	for _, n := range nodes {
+		if child, found := n.member(name); found {
+			res = append(res, child)
+		}
+	}
+	return res
+}
+
+// allChildren returns the children of all nodes.
+func allChildren(nodes []node) []node {
+	res := make([]node, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n.children()...)
+	}
+	return res
+}
+
+// descendants returns the nodes and all their descendants, parents before children.
+func descendants(nodes []node) []node {
+	res := make([]node, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n)
+		res = append(res, descendants(n.children())...)
+	}
+	return res
+}
@@ -0,0 +1,122 @@
+package testo
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/testo/internal/parser"
+)
+
+const selectorDoc = `{
+	"data": {
+		"items": [
+			{"id": 1, "name": "a", "price": 5, "tags": ["x"]},
+			{"id": 2, "name": "b", "price": 15},
+			{"id": 3, "name": "c", "price": 10.5, "tags": []}
+		],
+		"a/b": {"~c": true}
+	},
+	"meta": {"id": "req-1", "next": null}
+}`
+
+func TestSelectNodes(t *testing.T) {
+	doc, err := parser.DecodeJSON([]byte(selectorDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		selector string
+		paths    []string
+	}{
+		{"", []string{""}},
+		{"/data/items/1/name", []string{"/data/items/1/name"}},
+		{"/data/a~1b/~0c", []string{"/data/a~1b/~0c"}},
+		{"/data/items/3", nil},
+		{"/data/items/x", nil},
+		{"$", []string{""}},
+		{"$.meta.id", []string{"/meta/id"}},
+		{"$['meta']['next']", []string{"/meta/next"}},
+		{`$.data["a/b"]`, []string{"/data/a~1b"}},
+		{"$.data.items[-1]", []string{"/data/items/2"}},
+		{"$.data.items[0, 2].id", []string{"/data/items/0/id", "/data/items/2/id"}},
+		{"$.data.items[*].id", []string{"/data/items/0/id", "/data/items/1/id", "/data/items/2/id"}},
+		{"$.meta.*", []string{"/meta/id", "/meta/next"}},
+		{"$..id", []string{"/data/items/0/id", "/data/items/1/id", "/data/items/2/id", "/meta/id"}},
+		{"$..tags[0]", []string{"/data/items/0/tags/0"}},
+		{"$.data.items[?(@.id==3)]", []string{"/data/items/2"}},
+		{"$.data.items[?(@.price >= 10)].name", []string{"/data/items/1/name", "/data/items/2/name"}},
+		{"$.data.items[?(@.name != 'b')].id", []string{"/data/items/0/id", "/data/items/2/id"}},
+		{"$.data.items[?@.tags].id", []string{"/data/items/0/id", "/data/items/2/id"}},
+		{"$.data.items[?(@.tags[0] == \"x\")].id", []string{"/data/items/0/id"}},
+		{"$.meta[?(@ == null)]", []string{"/meta/next"}},
+		{"$.nope.id", nil},
+	}
+	for _, tt := range tests {
+		nodes, err := selectNodes(doc, tt.selector)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tt.selector, err)
+		}
+		paths := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			paths = append(paths, pointerOf(n))
+		}
+		if !slices.Equal(paths, tt.paths) {
+			t.Fatalf("unexpected nodes for %q: %v", tt.selector, paths)
+		}
+	}
+}
+
+// pointerOf returns the JSON Pointer to the node.
+func pointerOf(n node) string {
+	var b strings.Builder
+	for _, s := range n.steps {
+		b.WriteByte('/')
+		if s.isIndex {
+			b.WriteString(strconv.Itoa(s.index))
+		} else {
+			name := strings.ReplaceAll(s.name, "~", "~0")
+			b.WriteString(strings.ReplaceAll(name, "/", "~1"))
+		}
+	}
+	return b.String()
+}
+
+func TestSelectNodes_Invalid(t *testing.T) {
+	for _, selector := range []string{
+		"data",
+		"$data",
+		"$.",
+		"$[",
+		"$['a'",
+		"$[x]",
+		"$[?(@.id == )]",
+		"$[?(@.id == 1]",
+		"$[?(id == 1)]",
+		"$['unterminated]",
+	} {
+		if _, err := selectNodes(nil, selector); err == nil {
+			t.Fatalf("expected an error for %q", selector)
+		}
+	}
+}
+
+func TestCompare_Numbers(t *testing.T) {
+	tenth, _ := parser.ToRat(json.Number("0.1"))
+	for _, got := range []any{json.Number("0.1"), json.Number("1e-1"), 0.1, float32(0.1)} {
+		if !compare(got, "==", tenth) {
+			t.Fatalf("expected %v (%T) to be equal to 0.1", got, got)
+		}
+	}
+	three, _ := parser.ToRat(3)
+	for _, got := range []any{3, int64(3), uint8(3), json.Number("3.0")} {
+		if !compare(got, "==", three) {
+			t.Fatalf("expected %v (%T) to be equal to 3", got, got)
+		}
+	}
+	if compare("3", "==", three) {
+		t.Fatal("a string must not be equal to a number")
+	}
+}
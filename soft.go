@@ -0,0 +1,116 @@
+package testo
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"text/tabwriter"
+)
+
+// SoftT collects failed assertions and reports them together when the test finishes.
+//
+// Create it with [Soft].
+type SoftT struct {
+	t        testing.TB
+	mu       sync.Mutex
+	total    int
+	failures []softFailure
+}
+
+// softFailure is a failed soft assertion.
+type softFailure struct {
+	failure
+	// The number of the assertion in the test, starting from 1.
+	index int
+	// The place in the test where the assertion was called, like "api_test.go:42".
+	location string
+}
+
+// Soft starts a scope of soft assertions for the test.
+//
+// Failed assertions don't stop the test. Instead, they are collected
+// and reported together, with a summary table, when the test finishes.
+//
+// The returned value is safe for concurrent use by multiple goroutines.
+func Soft(t testing.TB) *SoftT {
+	t.Helper()
+	s := &SoftT{t: t}
+	t.Cleanup(s.report)
+	return s
+}
+
+// Check if the given input matches the expected pattern and record the failure if it doesn't.
+//
+// The test continues in any case. See [Assert] for the supported types of input.
+func (s *SoftT) Assert(given any, expected string, opts ...Option) {
+	s.t.Helper()
+	location := "unknown"
+	if _, file, line, ok := runtime.Caller(1); ok {
+		location = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	var f *failure
+	p, err := patterns.compile(expected, opts)
+	if err != nil {
+		msg := fmt.Sprintf("invalid pattern: %v", err)
+		f = &failure{message: msg, summary: msg}
+	} else {
+		f = p.check(given)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	if f != nil {
+		s.failures = append(s.failures, softFailure{failure: *f, index: s.total, location: location})
+	}
+}
+
+// report fails the test with the description of all collected failures.
+func (s *SoftT) report() {
+	s.t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.failures) == 0 {
+		return
+	}
+	s.t.Error(softMessage(s.total, s.failures))
+}
+
+// softMessage describes the failed soft assertions.
+//
+// The summary table lists every failed assertion in one line,
+// followed by the full description of each failure.
+func softMessage(total int, failures []softFailure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d soft assertions failed:\n\n", len(failures), total)
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  #\tlocation\tmismatches\tfirst mismatch")
+	for _, f := range failures {
+		mismatches := "-"
+		if f.mismatches > 0 {
+			mismatches = fmt.Sprint(f.mismatches)
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", f.index, f.location, mismatches, shortSummary(f.summary))
+	}
+	_ = w.Flush()
+	for _, f := range failures {
+		fmt.Fprintf(&b, "\n#%d at %s:\n%s\n", f.index, f.location, f.message)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// maxSummary is the maximum length of the failure summary in the table.
+const maxSummary = 80
+
+// shortSummary shortens the first line of the failure to fit the table.
+func shortSummary(s string) string {
+	s, _, _ = strings.Cut(s, "\n")
+	runes := []rune(s)
+	if len(runes) > maxSummary {
+		return string(runes[:maxSummary-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package testo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/orsinium-labs/testo"
+)
+
+func TestSoft(t *testing.T) {
+	ft := &fakeT{}
+	s := testo.Soft(ft)
+	s.Assert(`{"id": 1}`, `{"id": uint}`)
+	s.Assert(`{"id": -1}`, `{"id": uint}`)
+	s.Assert(`[1, "2", "3"]`, `[int, int, int]`)
+	s.Assert(`{}`, `{"id": }`)
+	if len(ft.errors) != 0 || ft.fatal {
+		t.Fatalf("failures must be reported at cleanup, got %v", ft.errors)
+	}
+	ft.finish()
+	if len(ft.errors) != 1 || ft.fatal {
+		t.Fatalf("expected one non-fatal failure, got %v", ft.errors)
+	}
+	msg := ft.errors[0]
+	for _, want := range []string{
+		"3 of 4 soft assertions failed:",
+		"  2  soft_test.go:14  1           input:1:8: id: must be greater than or equal to 0",
+		"  3  soft_test.go:15  2           input:1:5: [1..2]: invalid type",
+		"  4  soft_test.go:16  -           invalid pattern: ",
+		"#2 at soft_test.go:14:\nvalidation error:",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("the message doesn't contain %q:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "soft_test.go:13") {
+		t.Errorf("passed assertions must not be reported:\n%s", msg)
+	}
+}
+
+func TestSoft_NoFailures(t *testing.T) {
+	ft := &fakeT{}
+	s := testo.Soft(ft)
+	s.Assert(`[1, 2]`, `ints`)
+	ft.finish()
+	if len(ft.errors) != 0 {
+		t.Fatalf("unexpected failures: %v", ft.errors)
+	}
+}
@@ -2,7 +2,6 @@ package testo
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"testing"
 
@@ -17,68 +16,146 @@ import (
 //   - io.Reader returning JSON. For example, http.Response.Body.
 //   - string containing JSON.
 //   - []byte containing JSON.
-//   - an arbitrary object that can be validated with [valdo].
-func Assert(t *testing.T, given any, expected string) {
+//   - any other Go value, like a struct or a map. It's validated as if it was
+//     encoded into JSON with [json.Marshal], honoring struct tags and custom marshalers.
+//
+// The t can be [*testing.T], [*testing.B], [*testing.F], or any other [testing.TB].
+func Assert(t testing.TB, given any, expected string, opts ...Option) {
 	t.Helper()
-	parsed, err := readInput(given)
+	p, err := patterns.compile(expected, opts)
 	if err != nil {
-		t.Fatalf("failed to read input: %v", err)
+		t.Fatalf("invalid pattern: %v", err)
 	}
-	err = parser.Validate(parsed, expected)
+	p.Assert(t, given)
+}
+
+// Fail tests if the values selected from the given input don't match the expected pattern.
+//
+// The selector is either a JSON Pointer (RFC 6901), like "/data/items/0",
+// or a JSONPath expression, like "$.data.items[?(@.id==3)]". If the selector
+// matches several values, all of them must match the pattern. The supported JSONPath segments are:
+//
+//   - `.name` and `['name']`: the property of an object;
+//   - `[0]` and `[-1]`: the array item, counting from the end if negative;
+//   - `['a','b']` and `[0,1]`: several properties or items;
+//   - `.*` and `[*]`: all items of an array or values of an object;
+//   - `..name`: the property of the value or of any value nested in it;
+//   - `[?(@.price < 10)]`: items or values for which the filter is true.
+//
+// The test fails if the selector matches nothing. Failure messages include the selector,
+// and the paths of the mismatches start from the root of the input.
+//
+// See [Assert] for the supported types of input.
+func AssertAt(t testing.TB, given any, selector, expected string, opts ...Option) {
+	t.Helper()
+	p, err := patterns.compile(expected, opts)
 	if err != nil {
-		givenJSONBytes, marshalErr := json.MarshalIndent(parsed, "", "  ")
-		var givenJSONStr string
-		if marshalErr != nil {
-			givenJSONStr = fmt.Sprintf("input cannot be serialized: %v", err)
-		} else {
-			givenJSONStr = string(givenJSONBytes)
-		}
-		t.Fatalf(
-			"validation error: %v\n\ninput:\n%s\n\nschema:\n%s",
-			err, givenJSONStr, expected,
-		)
+		t.Fatalf("invalid pattern: %v", err)
 	}
+	p.AssertAt(t, given, selector)
+}
+
+// Mark the test as failed if the given input doesn't match the expected pattern.
+//
+// Unlike [Assert], the test continues, so that all failed checks in the test are reported.
+// Returns true if the input matches the pattern.
+//
+// See [Assert] for the supported types of input.
+func Check(t testing.TB, given any, expected string, opts ...Option) bool {
+	t.Helper()
+	p, err := patterns.compile(expected, opts)
+	if err != nil {
+		t.Errorf("invalid pattern: %v", err)
+		return false
+	}
+	return p.Check(t, given)
+}
+
+// input is the value to validate.
+type input struct {
+	value any
+	// The JSON text the value is decoded from, or nil if the input isn't JSON.
+	raw []byte
+	// The name of the input in failure messages, like the file name.
+	name string
 }
 
-func readInput(raw any) (any, error) {
+// inputName is the name of the input in failure messages if the input isn't a file.
+const inputName = "input"
+
+func readInput(raw any) (input, error) {
 	switch typed := raw.(type) {
 	case io.Reader:
 		rawAll, err := io.ReadAll(typed)
 		if err != nil {
-			return nil, err
+			return input{}, err
+		}
+		name := inputName
+		// The input is a file, like *os.File.
+		named, ok := typed.(interface{ Name() string })
+		if ok {
+			name = named.Name()
 		}
-		var parsed any
-		err = json.Unmarshal(rawAll, &parsed)
-		return parsed, err
+		return decodeInput(rawAll, name)
 	case string:
-		var parsed any
-		err := json.Unmarshal([]byte(typed), &parsed)
-		return parsed, err
+		return decodeInput([]byte(typed), inputName)
 	case []byte:
-		var parsed any
-		err := json.Unmarshal(typed, &parsed)
-		return parsed, err
+		return decodeInput(typed, inputName)
 	default:
-		return raw, nil
+		return normalizeInput(raw)
+	}
+}
+
+// normalizeInput converts the Go value into the JSON data model, like it was decoded from JSON.
+//
+// The value is encoded with [json.Marshal], so struct tags, `omitempty`, and custom
+// marshalers are honored, and validating a value is the same as validating its JSON.
+// Positions of mismatches aren't reported for the value.
+func normalizeInput(value any) (input, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return input{}, err
+	}
+	in, err := decodeInput(raw, inputName)
+	if err != nil {
+		return input{}, err
 	}
+	in.raw = nil
+	return in, nil
+}
+
+// decodeInput decodes the JSON input, keeping the raw text to find positions of values.
+func decodeInput(raw []byte, name string) (input, error) {
+	value, err := parser.DecodeJSON(raw)
+	if err != nil {
+		return input{}, err
+	}
+	return input{value: value, raw: raw, name: name}, nil
 }
 
 // Validate that the given JSON message matches the expected pattern.
-func ValidateJSON[T []byte | string](given T, expected string) error {
-	var parsed any
-	err := json.Unmarshal([]byte(given), &parsed)
+func ValidateJSON[T []byte | string](given T, expected string, opts ...Option) error {
+	p, err := patterns.compile(expected, opts)
 	if err != nil {
 		return err
 	}
-	return parser.Validate(parsed, expected)
+	return p.ValidateJSON([]byte(given))
 }
 
 // Validate that the given Go value matches the expected pattern.
-func Validate(given any, expected string) error {
-	return parser.Validate(given, expected)
+func Validate(given any, expected string, opts ...Option) error {
+	p, err := patterns.compile(expected, opts)
+	if err != nil {
+		return err
+	}
+	return p.Validate(given)
 }
 
 // Convert the pattern to a [valdo.Validator].
-func Parse(input string) (valdo.Validator, error) {
-	return parser.Parse(input)
+func Parse(input string, opts ...Option) (valdo.Validator, error) {
+	validator, err := parser.Parse(input, makeOptions(opts).config)
+	if err != nil {
+		return nil, newSyntaxError(err, input)
+	}
+	return validator, nil
 }